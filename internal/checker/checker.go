@@ -1,21 +1,38 @@
 package checker
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/andinianst93/system-health-checker/internal/models"
 )
 
+// DefaultCheckTimeout bounds a single check when no timeout is configured
+const DefaultCheckTimeout = 10 * time.Second
+
 // HealthChecker performs system health checks
 type HealthChecker struct {
-	metrics      *models.SystemMetrics
-	thresholds   *models.Thresholds
-	outputFormat string
+	metrics        *models.SystemMetrics
+	thresholds     *models.Thresholds
+	outputFormat   string
+	registry       *Registry
+	defaultTimeout time.Duration
+	timeouts       map[string]time.Duration
 }
 
 // Constructor with validation
 func NewHealthChecker(thresholds *models.Thresholds, format string) (*HealthChecker, error) {
+	return NewHealthCheckerWithRegistry(thresholds, format, NewDefaultRegistry())
+}
+
+// NewHealthCheckerWithRegistry is like NewHealthChecker but runs only
+// the checks in registry
+func NewHealthCheckerWithRegistry(thresholds *models.Thresholds, format string, registry *Registry) (*HealthChecker, error) {
 	// - IF format is not "table" AND not "json" THEN
 	//     return nil, error with message "invalid format: must be 'table' or 'json'"
 
@@ -27,77 +44,211 @@ func NewHealthChecker(thresholds *models.Thresholds, format string) (*HealthChec
 	if thresholds == nil {
 		thresholds = models.NewDefaultThresholds()
 	}
+	// - IF thresholds are invalid THEN return nil, error listing every problem
+	if err := thresholds.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid thresholds:\n%w", err)
+	}
 
 	// - Create new HealthChecker struct
 	// - Set metrics = models.NewSystemMetrics()
 	// - Set thresholds from parameter
 	// - Set outputFormat from parameter
+	// - Set registry from parameter
+	// - Set defaultTimeout = DefaultCheckTimeout
 	// - Return pointer to HealthChecker, nil error
 	return &HealthChecker{
 		models.NewSystemMetrics(),
 		thresholds,
 		format,
+		registry,
+		DefaultCheckTimeout,
+		make(map[string]time.Duration),
 	}, nil
 }
 
-// CheckAll runs all health checks
-func (hc *HealthChecker) CheckAll() error {
-	// - Call hc.CheckCPU()
-	err := hc.CheckCPU()
-	//   IF error THEN return wrapped error "CPU check failed: %w"
-	if err != nil {
-		return fmt.Errorf("CPU check failed: %w", err)
+// SetTimeout sets the timeout used by checks without their own timeout
+func (hc *HealthChecker) SetTimeout(timeout time.Duration) {
+	hc.defaultTimeout = timeout
+}
+
+// SetCheckTimeout sets the timeout for the check registered under name.
+// Names that are neither registered nor built in are rejected, so a typo
+// does not silently leave the default timeout in place.
+func (hc *HealthChecker) SetCheckTimeout(name string, timeout time.Duration) error {
+	if !hc.registry.Has(name) && !slices.Contains(BuiltinCheckNames(), name) {
+		return fmt.Errorf("unknown check in timeout: %s (available: %s)", name, strings.Join(BuiltinCheckNames(), ", "))
 	}
-	// - Call hc.CheckMemory()
-	err = hc.CheckMemory()
-	//   IF error THEN return wrapped error "memory check failed: %w"
-	if err != nil {
-		return fmt.Errorf("memory check failed: %w", err)
+	hc.timeouts[name] = timeout
+	return nil
+}
+
+// timeoutFor returns the timeout that applies to the named check
+func (hc *HealthChecker) timeoutFor(name string) time.Duration {
+	if timeout, ok := hc.timeouts[name]; ok {
+		return timeout
 	}
-	// - Call hc.CheckDisk()
-	err = hc.CheckDisk()
-	//   IF error THEN return wrapped error "disk check failed: %w"
-	if err != nil {
-		return fmt.Errorf("disk check failed: %w", err)
+	return hc.defaultTimeout
+}
+
+// Register adds an extra check to run after the built-in ones
+func (hc *HealthChecker) Register(check Check) error {
+	return hc.registry.Register(check)
+}
+
+// CheckAll runs all registered checks concurrently, each under its own
+// timeout. A check that fails or does not finish in time is reported as
+// UNKNOWN with its error; CheckAll only fails when no check produced data.
+func (hc *HealthChecker) CheckAll(ctx context.Context) error {
+	checks := hc.registry.Checks()
+	errs := make([]error, len(checks))
+
+	// - FOR EACH check IN registry (concurrently):
+	//     - Call check.Collect with a context bounded by the check timeout
+	//     - Record its error, or the context error if it did not finish
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			errs[i] = hc.collect(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	// - FOR EACH check IN registry (in registration order):
+	//     - IF error THEN append an UNKNOWN result carrying the error
+	//     - ELSE append check.Evaluate(metrics, thresholds) to metrics.Results
+	failed := make([]error, 0)
+	for i, check := range checks {
+		var result *models.CheckResult
+		if err := errs[i]; err != nil {
+			result = newUnknownResult(check.Name(), err.Error())
+			failed = append(failed, fmt.Errorf("%s check failed: %w", check.Name(), err))
+		} else {
+			result = check.Evaluate(hc.metrics, hc.thresholds)
+		}
+		result.Name = check.Name()
+		hc.metrics.Results = append(hc.metrics.Results, result)
+	}
+
+	// - IF the parent context is done THEN return its error
+	// - IF every check failed THEN return the joined errors
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(checks) > 0 && len(failed) == len(checks) {
+		return fmt.Errorf("no check could be collected: %w", errors.Join(failed...))
 	}
-	// - Return nil (success)
 	return nil
 }
 
+// collect runs check.Collect under the check timeout. Collectors that
+// ignore ctx are abandoned once it expires rather than waited on.
+func (hc *HealthChecker) collect(ctx context.Context, check Check) error {
+	ctx, cancel := context.WithTimeout(ctx, hc.timeoutFor(check.Name()))
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Collect(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s: %w", hc.timeoutFor(check.Name()), ctx.Err())
+		}
+		return ctx.Err()
+	}
+}
+
+// UnknownResult is the typed result of a check that produced no data
+type UnknownResult struct {
+	Status models.Status `json:"status"`
+	Error  string        `json:"error"`
+}
+
+// newUnknownResult builds an UNKNOWN result explaining why a check has no data
+func newUnknownResult(name, reason string) *models.CheckResult {
+	result := models.NewCheckResult(name)
+	result.Status = models.StatusUnknown
+	result.Error = reason
+	result.AddRow(name, reason, result.Status, "")
+	result.Data = UnknownResult{
+		Status: result.Status,
+		Error:  reason,
+	}
+	return result
+}
+
 // GetMetrics returns pointer to metrics
 func (hc *HealthChecker) GetMetrics() *models.SystemMetrics {
 	// - Return hc.metrics
 	return hc.metrics
 }
 
-// GetOverallStatus determines overall system health
-func (hc *HealthChecker) GetOverallStatus() string {
-	// - Check CPU critical: IF CPUPercent >= CPUCritical THEN return "CRITICAL"
-	if hc.metrics.CPUPercent >= hc.thresholds.CPUCritical {
-		return "CRITICAL"
+// GetOverallStatus determines overall system health from the results
+// evaluated by CheckAll; outputs and exit codes must use this value
+func (hc *HealthChecker) GetOverallStatus() models.Status {
+	// - Start from StatusOK
+	// - FOR EACH result IN metrics.Results:
+	//     keep the worse of the current status and result.Status
+	overall := models.StatusOK
+	for _, result := range hc.metrics.Results {
+		overall = overall.Worse(result.Status)
 	}
-	// - Calculate memory percentage
-	memPercent := hc.metrics.GetMemoryPercent()
-	// - Check memory critical: IF memPercent >= MemCritical THEN return "CRITICAL"
-	if memPercent >= hc.thresholds.MemCritical {
-		return "CRITICAL"
+	return overall
+}
+
+// bytesToGB converts bytes to GiB for display
+func bytesToGB(bytes uint64) float64 {
+	return float64(bytes) / 1024.0 / 1024.0 / 1024.0
+}
+
+// counterDelta returns after - before for a monotonic counter, or 0 if
+// the counter was reset in between
+func counterDelta(before, after uint64) uint64 {
+	if after < before {
+		return 0
 	}
+	return after - before
+}
 
-	// - FOR EACH disk IN metrics.Disks:
-	//     IF disk.GetStatus(thresholds) equals "CRITICAL" THEN return "CRITICAL"
-	for _, disk := range hc.metrics.Disks {
-		if disk.GetStatus(hc.thresholds) == "CRITICAL" {
-			return "CRITICAL"
-		}
+// counterRate returns the per-second rate of a monotonic counter sampled
+// interval apart
+func counterRate(before, after uint64, interval time.Duration) float64 {
+	if interval <= 0 {
+		return 0.0
 	}
-	// - Check for warnings (same logic with Warning thresholds)
-	// - IF any warning found THEN return "WARNING"
+	return float64(counterDelta(before, after)) / interval.Seconds()
+}
 
-	for _, disk := range hc.metrics.Disks {
-		if disk.GetStatus(hc.thresholds) == "WARNING" {
-			return "WARNING"
-		}
+// sampleWindow takes a sample, waits until window has passed since it was
+// taken (or ctx is done) and takes another, returning both and the time
+// between them. With a window of zero or less only one sample is taken:
+// it is returned as after, before is the zero value and elapsed is 0.
+func sampleWindow[T any](ctx context.Context, window time.Duration, sample func() (T, error)) (before, after T, elapsed time.Duration, err error) {
+	start := time.Now()
+	first, err := sample()
+	if err != nil || window <= 0 {
+		return before, first, 0, err
+	}
+
+	// - Wait out the rest of the window; a timer is stopped on cancellation
+	timer := time.NewTimer(window - time.Since(start))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return before, after, 0, ctx.Err()
+	}
+
+	second := time.Now()
+	after, err = sample()
+	if err != nil {
+		return before, after, 0, err
 	}
-	// - Return "OK"
-	return "OK"
+	return first, after, second.Sub(start), nil
 }
@@ -1,24 +1,226 @@
 package checker
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
 	"github.com/shirou/gopsutil/v4/cpu"
 )
 
-// CheckCPU gets current CPU usage
-func (hc *HealthChecker) CheckCPU() error {
-	// PSEUDOCODE:
-	// - Call cpu.Percent(0, false) to get CPU percentage
-	percent, err := cpu.Percent(0, false)
-	//   Parameters: interval=0 (instant), percpu=false (total)
+// DefaultCPUInterval is the CPU sampling window used when none is configured
+const DefaultCPUInterval = time.Second
+
+// CPUCheck reports total and per-core CPU usage and a breakdown of CPU
+// time, all measured over a sampling window
+type CPUCheck struct {
+	Options CPUOptions
+	percent float64
+	cores   []float64
+	times   models.CPUTimes
+}
+
+// CPUResult is the typed result of the cpu check
+type CPUResult struct {
+	Percent     float64         `json:"percent"`
+	Interval    models.Duration `json:"interval"`
+	Cores       []float64       `json:"cores"`
+	HottestCore int             `json:"hottest_core"`
+	CoreStatus  models.Status   `json:"core_status"`
+	Times       CPUTimesResult  `json:"times"`
+	StealStatus models.Status   `json:"steal_status"`
+	Status      models.Status   `json:"status"`
+}
+
+// CPUTimesResult is the CPU time breakdown in percent
+type CPUTimesResult struct {
+	User   float64 `json:"user"`
+	System float64 `json:"system"`
+	Iowait float64 `json:"iowait"`
+	Steal  float64 `json:"steal"`
+	Idle   float64 `json:"idle"`
+}
+
+func (c *CPUCheck) Name() string {
+	return "cpu"
+}
+
+// Collect samples per-core CPU times twice, Options.Interval apart, and
+// derives usage from the difference. With a zero interval the values are
+// averages since boot.
+func (c *CPUCheck) Collect(ctx context.Context) error {
+	// - Call cpu.TimesWithContext(ctx, true) to get per-core times, twice
+	//   Options.Interval apart
+	before, after, _, err := sampleWindow(ctx, time.Duration(c.Options.Interval), func() ([]cpu.TimesStat, error) {
+		return cpu.TimesWithContext(ctx, true)
+	})
 	// - IF error THEN return error
 	if err != nil {
 		return err
 	}
-	// - IF percent array length > 0 THEN
-	//     set hc.metrics.CPUPercent = percent[0]
-	if len(percent) > 0 {
-		hc.metrics.CPUPercent = percent[0]
+	// - Without an interval compare against zero, i.e. since boot
+	if c.Options.Interval <= 0 {
+		before = make([]cpu.TimesStat, len(after))
+	}
+	if len(after) != len(before) {
+		return fmt.Errorf("number of CPUs changed while sampling")
 	}
+
+	// - FOR EACH core: compute busy percent from the deltas
+	// - Sum the deltas of all cores for the total and the breakdown
+	c.cores = make([]float64, len(after))
+	var total cpu.TimesStat
+	for i := range after {
+		delta := CPUDelta(before[i], after[i])
+		c.cores[i] = CPUBusyPercent(delta)
+		total = cpuAdd(total, delta)
+	}
+	c.percent = CPUBusyPercent(total)
+	c.times = CPUBreakdown(total)
 	// - Return nil
 	return nil
 }
+
+// Evaluate compares total usage, the hottest core and steal time
+// against the CPU thresholds
+func (c *CPUCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.CPUPercent = c.percent
+	metrics.CPUCores = c.cores
+	metrics.CPUTimes = c.times
+
+	result := models.NewCheckResult(c.Name())
+
+	// Total usage
+	status := models.EvaluateHigher(metrics.CPUPercent, thresholds.CPUWarning, thresholds.CPUCritical)
+	result.Status = result.Status.Worse(status)
+	result.AddRow(
+		"CPU Usage",
+		fmt.Sprintf("%.2f%%", metrics.CPUPercent),
+		status,
+		fmt.Sprintf("< %.0f%%", thresholds.CPUWarning),
+	)
+
+	// Hot core rule
+	hottest, hottestPercent := metrics.GetHottestCore()
+	coreStatus := models.StatusOK
+	if hottest >= 0 {
+		// A zero critical level keeps the rule WARNING-only
+		coreCritical := thresholds.CPUCoreCritical
+		if coreCritical == 0 {
+			coreCritical = math.Inf(1)
+		}
+		coreStatus = models.EvaluateHigher(hottestPercent, thresholds.CPUCoreWarning, coreCritical)
+		result.Status = result.Status.Worse(coreStatus)
+		result.AddRow(
+			"CPU Hottest Core",
+			fmt.Sprintf("cpu%d %.2f%% (%d cores)", hottest, hottestPercent, len(metrics.CPUCores)),
+			coreStatus,
+			fmt.Sprintf("< %.0f%%", thresholds.CPUCoreWarning),
+		)
+	}
+
+	// Time breakdown, judged by steal
+	t := metrics.CPUTimes
+	stealStatus := models.EvaluateHigher(t.Steal, thresholds.CPUStealWarning, thresholds.CPUStealCritical)
+	result.Status = result.Status.Worse(stealStatus)
+	result.AddRow(
+		"CPU Time",
+		fmt.Sprintf("user %.1f%% sys %.1f%% iowait %.1f%% steal %.1f%%", t.User, t.System, t.Iowait, t.Steal),
+		stealStatus,
+		fmt.Sprintf("steal < %.0f%%", thresholds.CPUStealWarning),
+	)
+
+	result.Data = CPUResult{
+		Percent:     metrics.CPUPercent,
+		Interval:    c.Options.Interval,
+		Cores:       metrics.CPUCores,
+		HottestCore: hottest,
+		CoreStatus:  coreStatus,
+		Times: CPUTimesResult{
+			User:   t.User,
+			System: t.System,
+			Iowait: t.Iowait,
+			Steal:  t.Steal,
+			Idle:   t.Idle,
+		},
+		StealStatus: stealStatus,
+		Status:      result.Status,
+	}
+	return result
+}
+
+// CPUDelta returns after - before for every CPU state
+func CPUDelta(before, after cpu.TimesStat) cpu.TimesStat {
+	return cpu.TimesStat{
+		User:    after.User - before.User,
+		System:  after.System - before.System,
+		Idle:    after.Idle - before.Idle,
+		Nice:    after.Nice - before.Nice,
+		Iowait:  after.Iowait - before.Iowait,
+		Irq:     after.Irq - before.Irq,
+		Softirq: after.Softirq - before.Softirq,
+		Steal:   after.Steal - before.Steal,
+	}
+}
+
+// cpuAdd returns a + b for every CPU state
+func cpuAdd(a, b cpu.TimesStat) cpu.TimesStat {
+	return cpu.TimesStat{
+		User:    a.User + b.User,
+		System:  a.System + b.System,
+		Idle:    a.Idle + b.Idle,
+		Nice:    a.Nice + b.Nice,
+		Iowait:  a.Iowait + b.Iowait,
+		Irq:     a.Irq + b.Irq,
+		Softirq: a.Softirq + b.Softirq,
+		Steal:   a.Steal + b.Steal,
+	}
+}
+
+// cpuTotal returns the time spent in all states. Guest time is already
+// included in user time on Linux, so it is not added again.
+func cpuTotal(t cpu.TimesStat) float64 {
+	return t.User + t.System + t.Idle + t.Nice + t.Iowait + t.Irq + t.Softirq + t.Steal
+}
+
+// CPUBusyPercent returns the share of time not spent idle or waiting on I/O
+func CPUBusyPercent(t cpu.TimesStat) float64 {
+	total := cpuTotal(t)
+	if total <= 0 {
+		return 0.0
+	}
+	busy := total - t.Idle - t.Iowait
+	return clampPercent(busy / total * 100)
+}
+
+// CPUBreakdown converts CPU times into percentages of the total
+func CPUBreakdown(t cpu.TimesStat) models.CPUTimes {
+	total := cpuTotal(t)
+	if total <= 0 {
+		return models.CPUTimes{}
+	}
+	percent := func(v float64) float64 {
+		return clampPercent(v / total * 100)
+	}
+	return models.CPUTimes{
+		User:   percent(t.User + t.Nice),
+		System: percent(t.System + t.Irq + t.Softirq),
+		Iowait: percent(t.Iowait),
+		Steal:  percent(t.Steal),
+		Idle:   percent(t.Idle),
+	}
+}
+
+// clampPercent keeps counter glitches from producing values outside 0-100;
+// NaN becomes 0
+func clampPercent(v float64) float64 {
+	if v < 0 || math.IsNaN(v) {
+		return 0
+	}
+	if v > 100 {
+		return 100
+	}
+	return v
+}
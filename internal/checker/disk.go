@@ -1,31 +1,74 @@
 package checker
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/andinianst93/system-health-checker/internal/models"
 	"github.com/shirou/gopsutil/v4/disk"
 )
 
-// CheckDisk gets disk usage for all partitions
-func (hc *HealthChecker) CheckDisk() error {
-	// - Call disk.Partitions(false) to get all partitions
-	partitions, err := disk.Partitions(false)
+// DiskCheck reports usage for every selected physical partition
+type DiskCheck struct {
+	Options DiskOptions
+	disks   []*models.DiskInfo
+}
+
+// DiskResult is the typed result of the disk check for one partition
+type DiskResult struct {
+	MountPoint  string        `json:"mount_point"`
+	Device      string        `json:"device"`
+	FSType      string        `json:"fstype"`
+	Options     []string      `json:"options"`
+	ReadOnly    bool          `json:"read_only"`
+	UsedBytes   uint64        `json:"used_bytes"`
+	TotalBytes  uint64        `json:"total_bytes"`
+	UsedPercent float64       `json:"used_percent"`
+	FreePercent float64       `json:"free_percent"`
+	FreeBytes   uint64        `json:"free_bytes"`
+	Status      models.Status `json:"status"`
+	// Rule is the free-space threshold that fired, e.g. "< 2.0GiB free"
+	Rule              string        `json:"rule,omitempty"`
+	InodesUsed        uint64        `json:"inodes_used"`
+	InodesTotal       uint64        `json:"inodes_total"`
+	InodesUsedPercent float64       `json:"inodes_used_percent"`
+	InodeStatus       models.Status `json:"inode_status"`
+}
+
+func (c *DiskCheck) Name() string {
+	return "disks"
+}
+
+// Collect gets disk usage for all partitions
+func (c *DiskCheck) Collect(ctx context.Context) error {
+	// - Call disk.PartitionsWithContext(ctx, false) to get all partitions
+	partitions, err := disk.PartitionsWithContext(ctx, false)
 	//   Parameter: all=false (only physical partitions)
 	// - IF error THEN return error
 	if err != nil {
 		return err
 	}
 	// - FOR EACH partition IN partitions:
-	//     - Call disk.Usage(partition.Mountpoint) to get usage
+	//     - IF ctx is done THEN return its error
+	//     - IF partition is not selected by the include/exclude rules THEN continue
+	//     - Call disk.UsageWithContext(ctx, partition.Mountpoint) to get usage
 	//     - IF error THEN continue (skip this partition)
 	//     - Create diskInfo = models.NewDiskInfo(
 	//         partition.Mountpoint,
 	//         usage.Used,
 	//         usage.Total
 	//       )
-	//     - Append diskInfo to hc.metrics.Disks
+	//     - Append diskInfo to c.disks
 
+	c.disks = make([]*models.DiskInfo, 0, len(partitions))
 	for _, partition := range partitions {
-		usage, err := disk.Usage(partition.Mountpoint)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !c.Options.Selects(partition.Mountpoint, partition.Fstype, partition.Device) {
+			continue
+		}
+		usage, err := disk.UsageWithContext(ctx, partition.Mountpoint)
 		if err != nil {
 			continue
 		}
@@ -35,8 +78,77 @@ func (hc *HealthChecker) CheckDisk() error {
 			usage.Used,
 			usage.Total,
 		)
-		hc.metrics.Disks = append(hc.metrics.Disks, diskInfo)
+		// usage.Free is bavail: root-reserved blocks are not usable by services
+		diskInfo.FreeBytes = usage.Free
+		diskInfo.Device = partition.Device
+		diskInfo.FSType = partition.Fstype
+		diskInfo.Options = partition.Opts
+		diskInfo.InodesUsed = usage.InodesUsed
+		diskInfo.InodesTotal = usage.InodesTotal
+		c.disks = append(c.disks, diskInfo)
 	}
 	// - Return nil
 	return nil
 }
+
+// Evaluate compares each partition's free space and inode usage against
+// the disk and inode thresholds
+func (c *DiskCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.Disks = c.disks
+
+	result := models.NewCheckResult(c.Name())
+	disks := make([]DiskResult, 0, len(metrics.Disks))
+	for _, d := range metrics.Disks {
+		spaceStatus, rule := d.Evaluate(thresholds)
+		inodeStatus := d.GetInodeStatus(thresholds)
+		status := spaceStatus.Worse(inodeStatus)
+		result.Status = result.Status.Worse(status)
+		threshold := rule
+		if threshold == "" {
+			threshold = diskWarningRule(thresholds.ForDisk(d.MountPoint))
+		}
+		result.AddRow(
+			fmt.Sprintf("Disk %s", d.MountPoint),
+			fmt.Sprintf("%.2fGB / %.2fGB (%.1f%% used)", bytesToGB(d.UsedBytes), bytesToGB(d.TotalBytes), d.GetUsedPercent()),
+			spaceStatus,
+			threshold,
+		)
+		if d.InodesTotal > 0 {
+			result.AddRow(
+				fmt.Sprintf("Inodes %s", d.MountPoint),
+				fmt.Sprintf("%d / %d (%.1f%% used)", d.InodesUsed, d.InodesTotal, d.GetInodePercent()),
+				inodeStatus,
+				fmt.Sprintf("< %.0f%%", thresholds.InodeWarning),
+			)
+		}
+		disks = append(disks, DiskResult{
+			MountPoint:        d.MountPoint,
+			Device:            d.Device,
+			FSType:            d.FSType,
+			Options:           d.Options,
+			ReadOnly:          d.ReadOnly(),
+			UsedBytes:         d.UsedBytes,
+			TotalBytes:        d.TotalBytes,
+			UsedPercent:       d.GetUsedPercent(),
+			FreePercent:       d.GetFreePercent(),
+			FreeBytes:         d.GetFreeBytes(),
+			Status:            status,
+			Rule:              rule,
+			InodesUsed:        d.InodesUsed,
+			InodesTotal:       d.InodesTotal,
+			InodesUsedPercent: d.GetInodePercent(),
+			InodeStatus:       inodeStatus,
+		})
+	}
+	result.Data = disks
+	return result
+}
+
+// diskWarningRule describes the warning rules that apply to a disk
+func diskWarningRule(limits models.DiskLimits) string {
+	rule := fmt.Sprintf("< %.0f%% free", limits.Warning)
+	if limits.WarningBytes > 0 {
+		rule += fmt.Sprintf(", < %s free", limits.WarningBytes)
+	}
+	return rule
+}
@@ -0,0 +1,164 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/host"
+)
+
+// DefaultDiskIOInterval is the disk I/O sampling window used when none is
+// configured
+const DefaultDiskIOInterval = time.Second
+
+// DiskIOCheck reports per-device throughput, IOPS, average await and
+// utilization, measured over a sampling window
+type DiskIOCheck struct {
+	// SysRoot is where sysfs is mounted; empty means DefaultSysRoot
+	SysRoot string
+	Options DiskIOOptions
+	devices []*models.DiskIOInfo
+}
+
+// DiskIOResult is the typed result of the diskio check for one device
+type DiskIOResult struct {
+	Device           string        `json:"device"`
+	ReadBytesPerSec  float64       `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64       `json:"write_bytes_per_sec"`
+	ReadIOPS         float64       `json:"read_iops"`
+	WriteIOPS        float64       `json:"write_iops"`
+	AwaitMs          float64       `json:"await_ms"`
+	UtilPercent      float64       `json:"util_percent"`
+	UtilStatus       models.Status `json:"util_status"`
+	AwaitStatus      models.Status `json:"await_status"`
+	Status           models.Status `json:"status"`
+}
+
+func (c *DiskIOCheck) Name() string {
+	return "diskio"
+}
+
+// Collect samples disk.IOCounters twice, Options.Interval apart, and
+// derives rates from the difference. With a zero interval the values are
+// averages since boot.
+func (c *DiskIOCheck) Collect(ctx context.Context) error {
+	// - Call disk.IOCountersWithContext(ctx) to get counters per device,
+	//   twice Options.Interval apart
+	before, after, window, err := sampleWindow(ctx, time.Duration(c.Options.Interval), func() (map[string]disk.IOCountersStat, error) {
+		return disk.IOCountersWithContext(ctx)
+	})
+	// - IF error THEN return error
+	if err != nil {
+		return err
+	}
+
+	// - Without an interval compare against zero over the uptime, i.e.
+	//   since boot
+	if c.Options.Interval <= 0 {
+		uptime, err := host.UptimeWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		window = time.Duration(uptime) * time.Second
+	}
+
+	// - FOR EACH selected device: compute rates from the deltas
+	//   (a device that appeared during the window has no first sample and
+	//   its since-boot counters would read as a burst, so skip it)
+	sampled := c.Options.Interval > 0
+	c.devices = make([]*models.DiskIOInfo, 0, len(after))
+	for name, a := range after {
+		if !c.Options.Selects(name) {
+			continue
+		}
+		if !c.Options.IncludePartitions && isPartition(c.SysRoot, name) {
+			continue
+		}
+		b, ok := before[name]
+		if sampled && !ok {
+			continue
+		}
+		c.devices = append(c.devices, DiskIORates(name, b, a, window))
+	}
+	sort.Slice(c.devices, func(i, j int) bool {
+		return c.devices[i].Device < c.devices[j].Device
+	})
+	// - Return nil
+	return nil
+}
+
+// Evaluate compares each device's utilization and await against the
+// disk I/O thresholds
+func (c *DiskIOCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.DiskIO = c.devices
+
+	result := models.NewCheckResult(c.Name())
+	devices := make([]DiskIOResult, 0, len(metrics.DiskIO))
+	for _, d := range metrics.DiskIO {
+		utilStatus := d.GetUtilStatus(thresholds)
+		awaitStatus := d.GetAwaitStatus(thresholds)
+		status := utilStatus.Worse(awaitStatus)
+		result.Status = result.Status.Worse(status)
+		result.AddRow(
+			fmt.Sprintf("Disk I/O %s", d.Device),
+			fmt.Sprintf("r %s/s w %s/s, %.0f IOPS, await %.1fms, util %.1f%%",
+				models.ByteSize(d.ReadBytesPerSec), models.ByteSize(d.WriteBytesPerSec),
+				d.ReadIOPS+d.WriteIOPS, d.AwaitMs, d.UtilPercent),
+			status,
+			fmt.Sprintf("util < %.0f%%, await < %.0fms", thresholds.DiskUtilWarning, thresholds.DiskAwaitWarning),
+		)
+		devices = append(devices, DiskIOResult{
+			Device:           d.Device,
+			ReadBytesPerSec:  d.ReadBytesPerSec,
+			WriteBytesPerSec: d.WriteBytesPerSec,
+			ReadIOPS:         d.ReadIOPS,
+			WriteIOPS:        d.WriteIOPS,
+			AwaitMs:          d.AwaitMs,
+			UtilPercent:      d.UtilPercent,
+			UtilStatus:       utilStatus,
+			AwaitStatus:      awaitStatus,
+			Status:           status,
+		})
+	}
+	result.Data = devices
+	return result
+}
+
+// DiskIORates turns two counter samples taken window apart into rates
+func DiskIORates(name string, before, after disk.IOCountersStat, window time.Duration) *models.DiskIOInfo {
+	info := &models.DiskIOInfo{Device: name}
+	// - Busy time is counted in whole milliseconds, so a shorter window
+	//   cannot yield a utilization
+	if window <= 0 || window.Milliseconds() == 0 {
+		return info
+	}
+
+	info.ReadBytesPerSec = counterRate(before.ReadBytes, after.ReadBytes, window)
+	info.WriteBytesPerSec = counterRate(before.WriteBytes, after.WriteBytes, window)
+	info.ReadIOPS = counterRate(before.ReadCount, after.ReadCount, window)
+	info.WriteIOPS = counterRate(before.WriteCount, after.WriteCount, window)
+
+	// - Await is the time spent on requests divided by completed requests
+	reads := counterDelta(before.ReadCount, after.ReadCount)
+	writes := counterDelta(before.WriteCount, after.WriteCount)
+	if ops := reads + writes; ops > 0 {
+		waited := counterDelta(before.ReadTime, after.ReadTime) + counterDelta(before.WriteTime, after.WriteTime)
+		info.AwaitMs = float64(waited) / float64(ops)
+	}
+	// - Utilization is the share of wall time the device had I/O in flight
+	busyMs := float64(counterDelta(before.IoTime, after.IoTime))
+	info.UtilPercent = clampPercent(busyMs / (window.Seconds() * 1000) * 100)
+	return info
+}
+
+// isPartition reports whether a Linux block device is a partition, i.e.
+// has a "partition" file in <sys root>/class/block/<name>
+func isPartition(sysRoot, name string) bool {
+	_, err := os.Stat(sysPath(sysRoot, "class", "block", name, "partition"))
+	return err == nil
+}
@@ -0,0 +1,194 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// KernelTablesCheck reports how full the system-wide file handle, PID and
+// conntrack tables are, read from procfs
+type KernelTablesCheck struct {
+	// ProcRoot is where procfs is mounted; empty means DefaultProcRoot
+	ProcRoot string
+	info     *models.KernelTablesInfo
+}
+
+// KernelTablesResult is the typed result of the kernel check
+type KernelTablesResult struct {
+	FileHandles       uint64           `json:"file_handles"`
+	FileHandlesMax    uint64           `json:"file_handles_max"`
+	FileHandlePercent float64          `json:"file_handle_percent"`
+	FileHandleStatus  models.Status    `json:"file_handle_status"`
+	Tasks             uint64           `json:"tasks"`
+	PIDMax            uint64           `json:"pid_max"`
+	ThreadsMax        uint64           `json:"threads_max"`
+	PIDPercent        float64          `json:"pid_percent"`
+	PIDStatus         models.Status    `json:"pid_status"`
+	Conntrack         *ConntrackResult `json:"conntrack,omitempty"`
+	Status            models.Status    `json:"status"`
+}
+
+// ConntrackResult is the conntrack part of the kernel check result
+type ConntrackResult struct {
+	Count   uint64        `json:"count"`
+	Max     uint64        `json:"max"`
+	Percent float64       `json:"percent"`
+	Status  models.Status `json:"status"`
+}
+
+func (c *KernelTablesCheck) Name() string {
+	return "kernel"
+}
+
+// Collect reads fs/file-nr, kernel/pid_max, kernel/threads-max, the task
+// count from loadavg and, when present, the nf_conntrack counters
+func (c *KernelTablesCheck) Collect(ctx context.Context) error {
+	info := &models.KernelTablesInfo{}
+
+	// - Read sys/fs/file-nr: allocated, unused, max
+	used, limit, err := readFileNr(procPath(c.ProcRoot, "sys", "fs", "file-nr"))
+	if err != nil {
+		return err
+	}
+	info.FileHandles = used
+	info.FileHandlesMax = limit
+
+	// - Read the task count and the PID and thread limits
+	if info.Tasks, err = readTaskCount(procPath(c.ProcRoot, "loadavg")); err != nil {
+		return err
+	}
+	if info.PIDMax, err = readProcUint(procPath(c.ProcRoot, "sys", "kernel", "pid_max")); err != nil {
+		return err
+	}
+	if info.ThreadsMax, err = readProcUint(procPath(c.ProcRoot, "sys", "kernel", "threads-max")); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// - IF nf_conntrack is loaded THEN read its count and max
+	count, err := readProcUint(procPath(c.ProcRoot, "sys", "net", "netfilter", "nf_conntrack_count"))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		info.HasConntrack = true
+		info.ConntrackCount = count
+		if info.ConntrackMax, err = readProcUint(procPath(c.ProcRoot, "sys", "net", "netfilter", "nf_conntrack_max")); err != nil {
+			return err
+		}
+	}
+
+	c.info = info
+	return nil
+}
+
+// Evaluate compares each table's fill level against its thresholds
+func (c *KernelTablesCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.KernelTables = c.info
+	ki := c.info
+
+	result := models.NewCheckResult(c.Name())
+
+	fileStatus := ki.GetFileHandleStatus(thresholds)
+	result.Status = result.Status.Worse(fileStatus)
+	result.AddRow(
+		"File Handles",
+		fmt.Sprintf("%d / %d (%.1f%%)", ki.FileHandles, ki.FileHandlesMax, ki.GetFileHandlePercent()),
+		fileStatus,
+		fmt.Sprintf("< %.0f%%", thresholds.FileHandleWarning),
+	)
+
+	pidStatus := ki.GetPIDStatus(thresholds)
+	result.Status = result.Status.Worse(pidStatus)
+	result.AddRow(
+		"Tasks",
+		fmt.Sprintf("%d (pid_max %d, threads-max %d, %.1f%%)", ki.Tasks, ki.PIDMax, ki.ThreadsMax, ki.GetPIDPercent()),
+		pidStatus,
+		fmt.Sprintf("< %.0f%%", thresholds.PIDWarning),
+	)
+
+	data := KernelTablesResult{
+		FileHandles:       ki.FileHandles,
+		FileHandlesMax:    ki.FileHandlesMax,
+		FileHandlePercent: ki.GetFileHandlePercent(),
+		FileHandleStatus:  fileStatus,
+		Tasks:             ki.Tasks,
+		PIDMax:            ki.PIDMax,
+		ThreadsMax:        ki.ThreadsMax,
+		PIDPercent:        ki.GetPIDPercent(),
+		PIDStatus:         pidStatus,
+	}
+
+	if ki.HasConntrack {
+		conntrackStatus := ki.GetConntrackStatus(thresholds)
+		result.Status = result.Status.Worse(conntrackStatus)
+		result.AddRow(
+			"Conntrack",
+			fmt.Sprintf("%d / %d (%.1f%%)", ki.ConntrackCount, ki.ConntrackMax, ki.GetConntrackPercent()),
+			conntrackStatus,
+			fmt.Sprintf("< %.0f%%", thresholds.ConntrackWarning),
+		)
+		data.Conntrack = &ConntrackResult{
+			Count:   ki.ConntrackCount,
+			Max:     ki.ConntrackMax,
+			Percent: ki.GetConntrackPercent(),
+			Status:  conntrackStatus,
+		}
+	}
+
+	data.Status = result.Status
+	result.Data = data
+	return result
+}
+
+// readFileNr reads fs/file-nr ("allocated unused max") and returns the
+// handles in use and the maximum
+func readFileNr(path string) (used, limit uint64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 3 {
+		return 0, 0, fmt.Errorf("parse %s: expected 3 fields, got %d", path, len(fields))
+	}
+	values := make([]uint64, 3)
+	for i, field := range fields {
+		if values[i], err = strconv.ParseUint(field, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+	return counterDelta(values[1], values[0]), values[2], nil
+}
+
+// readTaskCount reads the number of tasks from the "running/total" field
+// of loadavg
+func readTaskCount(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 4 {
+		return 0, fmt.Errorf("parse %s: expected at least 4 fields, got %d", path, len(fields))
+	}
+	_, total, ok := strings.Cut(fields[3], "/")
+	if !ok {
+		return 0, fmt.Errorf("parse %s: malformed task field %q", path, fields[3])
+	}
+	tasks, err := strconv.ParseUint(total, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/load"
+)
+
+// LoadCheck reports 1/5/15 minute load averages normalized by the number
+// of logical CPUs, plus the number of running and blocked processes
+type LoadCheck struct {
+	info *models.LoadInfo
+}
+
+// LoadResult is the typed result of the load check
+type LoadResult struct {
+	Load1        float64       `json:"load1"`
+	Load5        float64       `json:"load5"`
+	Load15       float64       `json:"load15"`
+	PerCPU1      float64       `json:"per_cpu1"`
+	PerCPU5      float64       `json:"per_cpu5"`
+	PerCPU15     float64       `json:"per_cpu15"`
+	CPUs         int           `json:"cpus"`
+	ProcsRunning int           `json:"procs_running"`
+	ProcsBlocked int           `json:"procs_blocked"`
+	Status       models.Status `json:"status"`
+}
+
+func (c *LoadCheck) Name() string {
+	return "load"
+}
+
+// Collect gets load averages, the logical CPU count and the run queue
+func (c *LoadCheck) Collect(ctx context.Context) error {
+	// - Call load.AvgWithContext(ctx) to get 1/5/15 minute averages
+	avg, err := load.AvgWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	// - Call cpu.CountsWithContext(ctx, true) to get logical CPUs
+	cpus, err := cpu.CountsWithContext(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	c.info = &models.LoadInfo{
+		Load1:  avg.Load1,
+		Load5:  avg.Load5,
+		Load15: avg.Load15,
+		CPUs:   cpus,
+	}
+	// - Call load.MiscWithContext(ctx) for running/blocked processes;
+	//   not every platform supports it, so errors are ignored
+	if misc, err := load.MiscWithContext(ctx); err == nil {
+		c.info.ProcsRunning = misc.ProcsRunning
+		c.info.ProcsBlocked = misc.ProcsBlocked
+	}
+	return nil
+}
+
+// Evaluate compares each per-CPU load average against the load thresholds
+func (c *LoadCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.Load = c.info
+	li := c.info
+
+	result := models.NewCheckResult(c.Name())
+	for _, avg := range []struct {
+		label string
+		value float64
+	}{{"1m", li.Load1}, {"5m", li.Load5}, {"15m", li.Load15}} {
+		perCPU := li.PerCPU(avg.value)
+		status := models.EvaluateHigher(perCPU, thresholds.LoadWarning, thresholds.LoadCritical)
+		result.Status = result.Status.Worse(status)
+		result.AddRow(
+			fmt.Sprintf("Load %s", avg.label),
+			fmt.Sprintf("%.2f (%.2f per CPU, %d CPUs)", avg.value, perCPU, li.CPUs),
+			status,
+			fmt.Sprintf("< %.2f per CPU", thresholds.LoadWarning),
+		)
+	}
+	result.AddRow(
+		"Run Queue",
+		fmt.Sprintf("%d running, %d blocked", li.ProcsRunning, li.ProcsBlocked),
+		models.StatusOK,
+		"",
+	)
+
+	result.Data = LoadResult{
+		Load1:        li.Load1,
+		Load5:        li.Load5,
+		Load15:       li.Load15,
+		PerCPU1:      li.PerCPU(li.Load1),
+		PerCPU5:      li.PerCPU(li.Load5),
+		PerCPU15:     li.PerCPU(li.Load15),
+		CPUs:         li.CPUs,
+		ProcsRunning: li.ProcsRunning,
+		ProcsBlocked: li.ProcsBlocked,
+		Status:       result.Status,
+	}
+	return result
+}
@@ -1,19 +1,165 @@
 package checker
 
-import "github.com/shirou/gopsutil/v4/mem"
+import (
+	"context"
+	"fmt"
+	"time"
 
-// CheckMemory gets current memory usage
-func (hc *HealthChecker) CheckMemory() error {
-	// - Call mem.VirtualMemory() to get memory stats
-	vmem, err := mem.VirtualMemory()
+	"github.com/andinianst93/system-health-checker/internal/models"
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+// DefaultSwapInterval is the swap activity sampling window used when none
+// is configured
+const DefaultSwapInterval = time.Second
+
+// MemoryCheck reports memory usage based on available memory, the
+// buffers/cache breakdown, and swap usage and activity
+type MemoryCheck struct {
+	Options   MemoryOptions
+	used      uint64
+	total     uint64
+	available uint64
+	buffers   uint64
+	cached    uint64
+	swapUsed  uint64
+	swapTotal uint64
+	swapIn    float64
+	swapOut   float64
+}
+
+// MemoryResult is the typed result of the memory check
+type MemoryResult struct {
+	UsedBytes      uint64        `json:"used_bytes"`
+	TotalBytes     uint64        `json:"total_bytes"`
+	AvailableBytes uint64        `json:"available_bytes"`
+	BuffersBytes   uint64        `json:"buffers_bytes"`
+	CachedBytes    uint64        `json:"cached_bytes"`
+	Percent        float64       `json:"percent"`
+	Swap           SwapResult    `json:"swap"`
+	Status         models.Status `json:"status"`
+}
+
+// SwapResult is the swap part of the memory check result
+type SwapResult struct {
+	UsedBytes      uint64          `json:"used_bytes"`
+	TotalBytes     uint64          `json:"total_bytes"`
+	Percent        float64         `json:"percent"`
+	InBytesPerSec  float64         `json:"in_bytes_per_sec"`
+	OutBytesPerSec float64         `json:"out_bytes_per_sec"`
+	Interval       models.Duration `json:"interval"`
+	Status         models.Status   `json:"status"`
+}
+
+func (c *MemoryCheck) Name() string {
+	return "memory"
+}
+
+// Collect gets current memory and swap usage, and samples swap-in/out
+// counters Options.SwapInterval apart to derive swap activity rates
+func (c *MemoryCheck) Collect(ctx context.Context) error {
+	// - Call mem.VirtualMemoryWithContext(ctx) to get memory stats
+	vmem, err := mem.VirtualMemoryWithContext(ctx)
 	// - IF error THEN return error
 	if err != nil {
 		return err
 	}
-	// - Set hc.metrics.MemoryUsed = vmem.Used
-	hc.metrics.MemoryUsed = vmem.Used
-	// - Set hc.metrics.MemoryTotal = vmem.Total
-	hc.metrics.MemoryTotal = vmem.Total
+	// - Keep used/total plus available and the buffers/cache breakdown
+	c.used = vmem.Used
+	c.total = vmem.Total
+	c.available = vmem.Available
+	c.buffers = vmem.Buffers
+	c.cached = vmem.Cached
+
+	// - Call mem.SwapMemoryWithContext(ctx) to get swap stats, twice
+	//   SwapInterval apart when it is set
+	before, after, elapsed, err := sampleWindow(ctx, time.Duration(c.Options.SwapInterval), func() (*mem.SwapMemoryStat, error) {
+		return mem.SwapMemoryWithContext(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	c.swapUsed = after.Used
+	c.swapTotal = after.Total
+
+	// - IF sampled twice THEN turn the swap-in/out counter deltas into rates
+	if before != nil {
+		c.swapIn = counterRate(before.Sin, after.Sin, elapsed)
+		c.swapOut = counterRate(before.Sout, after.Sout, elapsed)
+	}
 	// - Return nil
 	return nil
 }
+
+// Evaluate compares memory in use (total minus available) and swap usage
+// against the memory and swap thresholds
+func (c *MemoryCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.MemoryUsed = c.used
+	metrics.MemoryTotal = c.total
+	metrics.MemoryAvailable = c.available
+	metrics.MemoryBuffers = c.buffers
+	metrics.MemoryCached = c.cached
+	metrics.SwapUsed = c.swapUsed
+	metrics.SwapTotal = c.swapTotal
+	metrics.SwapInRate = c.swapIn
+	metrics.SwapOutRate = c.swapOut
+
+	result := models.NewCheckResult(c.Name())
+
+	// Memory, judged by what is not available (page cache does not count)
+	memPercent := metrics.GetMemoryPercent()
+	memStatus := models.EvaluateHigher(memPercent, thresholds.MemWarning, thresholds.MemCritical)
+	result.Status = result.Status.Worse(memStatus)
+	result.AddRow(
+		"Memory Usage",
+		fmt.Sprintf("%.2fGB available / %.2fGB (%.1f%% in use)", bytesToGB(metrics.MemoryAvailable), bytesToGB(metrics.MemoryTotal), memPercent),
+		memStatus,
+		fmt.Sprintf("< %.0f%%", thresholds.MemWarning),
+	)
+	result.AddRow(
+		"Memory Cache",
+		fmt.Sprintf("%.2fGB used, %.2fGB buffers, %.2fGB cached", bytesToGB(metrics.MemoryUsed), bytesToGB(metrics.MemoryBuffers), bytesToGB(metrics.MemoryCached)),
+		models.StatusOK,
+		"",
+	)
+
+	// Swap; a host without swap is always OK
+	swapPercent := metrics.GetSwapPercent()
+	swapStatus := models.StatusOK
+	swapValue := "no swap"
+	if metrics.SwapTotal > 0 {
+		swapStatus = models.EvaluateHigher(swapPercent, thresholds.SwapWarning, thresholds.SwapCritical)
+		swapValue = fmt.Sprintf("%.2fGB / %.2fGB (%.1f%%)", bytesToGB(metrics.SwapUsed), bytesToGB(metrics.SwapTotal), swapPercent)
+		if c.Options.SwapInterval > 0 {
+			swapValue += fmt.Sprintf(", in %s/s out %s/s",
+				models.ByteSize(metrics.SwapInRate), models.ByteSize(metrics.SwapOutRate))
+		}
+	}
+	result.Status = result.Status.Worse(swapStatus)
+	result.AddRow(
+		"Swap Usage",
+		swapValue,
+		swapStatus,
+		fmt.Sprintf("< %.0f%%", thresholds.SwapWarning),
+	)
+
+	result.Data = MemoryResult{
+		UsedBytes:      metrics.MemoryUsed,
+		TotalBytes:     metrics.MemoryTotal,
+		AvailableBytes: metrics.MemoryAvailable,
+		BuffersBytes:   metrics.MemoryBuffers,
+		CachedBytes:    metrics.MemoryCached,
+		Percent:        memPercent,
+		Swap: SwapResult{
+			UsedBytes:      metrics.SwapUsed,
+			TotalBytes:     metrics.SwapTotal,
+			Percent:        swapPercent,
+			InBytesPerSec:  metrics.SwapInRate,
+			OutBytesPerSec: metrics.SwapOutRate,
+			Interval:       c.Options.SwapInterval,
+			Status:         swapStatus,
+		},
+		Status: result.Status,
+	}
+	return result
+}
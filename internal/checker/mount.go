@@ -0,0 +1,115 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// MountCheck verifies that required mount points are present and that
+// mounts expected to be writable have not been remounted read-only
+type MountCheck struct {
+	// ProcRoot is where procfs is mounted; empty means DefaultProcRoot
+	ProcRoot string
+	Options  DiskOptions
+	mounts   []*models.MountInfo
+}
+
+// MountResult is the typed result of the mounts check for one mount point
+type MountResult struct {
+	MountPoint string        `json:"mount_point"`
+	Device     string        `json:"device,omitempty"`
+	FSType     string        `json:"fstype,omitempty"`
+	Options    []string      `json:"options,omitempty"`
+	Present    bool          `json:"present"`
+	ReadOnly   bool          `json:"read_only"`
+	Required   bool          `json:"required"`
+	Writable   bool          `json:"expected_writable"`
+	Status     models.Status `json:"status"`
+}
+
+func (c *MountCheck) Name() string {
+	return "mounts"
+}
+
+// Collect reads the mount table, including network and virtual filesystems
+func (c *MountCheck) Collect(ctx context.Context) error {
+	c.mounts = make([]*models.MountInfo, 0)
+	// - IF no required or writable mounts are configured THEN nothing to do
+	if len(c.Options.RequiredMounts) == 0 && len(c.Options.WritableMounts) == 0 {
+		return nil
+	}
+
+	// - Call disk.PartitionsWithContext(ctx, true) to get every mount
+	partitions, err := disk.PartitionsWithContext(withProcRoot(ctx, c.ProcRoot), true)
+	if err != nil {
+		return err
+	}
+
+	// - FOR EACH required mount point:
+	//     - record it as present (with its options) or missing
+	seen := make(map[string]bool)
+	for _, required := range c.Options.RequiredMounts {
+		info := models.NewMountInfo(required)
+		info.Required = true
+		for _, p := range partitions {
+			if p.Mountpoint == required {
+				info.SetPartition(p.Device, p.Fstype, p.Opts)
+			}
+		}
+		c.mounts = append(c.mounts, info)
+		seen[required] = true
+	}
+
+	// - FOR EACH other mount matching the writable patterns: record it
+	for _, p := range partitions {
+		if seen[p.Mountpoint] || !matchAny(p.Mountpoint, c.Options.WritableMounts) {
+			continue
+		}
+		info := models.NewMountInfo(p.Mountpoint)
+		info.SetPartition(p.Device, p.Fstype, p.Opts)
+		c.mounts = append(c.mounts, info)
+		seen[p.Mountpoint] = true
+	}
+	return nil
+}
+
+// Evaluate flags missing required mounts and read-only writable mounts as CRITICAL
+func (c *MountCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.Mounts = c.mounts
+
+	result := models.NewCheckResult(c.Name())
+	mounts := make([]MountResult, 0, len(c.mounts))
+	for _, m := range c.mounts {
+		writable := matchAny(m.MountPoint, c.Options.WritableMounts)
+		status := m.GetStatus(writable)
+		result.Status = result.Status.Worse(status)
+
+		value := "missing"
+		if m.Present {
+			value = fmt.Sprintf("mounted (%s)", strings.Join(m.Options, ","))
+		}
+		expected := "mounted"
+		if writable {
+			expected = "mounted read-write"
+		}
+		result.AddRow(fmt.Sprintf("Mount %s", m.MountPoint), value, status, expected)
+
+		mounts = append(mounts, MountResult{
+			MountPoint: m.MountPoint,
+			Device:     m.Device,
+			FSType:     m.FSType,
+			Options:    m.Options,
+			Present:    m.Present,
+			ReadOnly:   m.ReadOnly(),
+			Required:   m.Required,
+			Writable:   writable,
+			Status:     status,
+		})
+	}
+	result.Data = mounts
+	return result
+}
@@ -0,0 +1,199 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+// DefaultNetworkInterval is the network sampling window used when none is
+// configured
+const DefaultNetworkInterval = time.Second
+
+// NetworkCheck reports per-interface link state, throughput and error and
+// drop rates, measured over a sampling window
+type NetworkCheck struct {
+	// SysRoot is where sysfs is mounted; empty means DefaultSysRoot
+	SysRoot    string
+	Options    NetworkOptions
+	interfaces []*models.NetInterfaceInfo
+}
+
+// NetworkResult is the typed result of the network check for one interface
+type NetworkResult struct {
+	Name            string        `json:"name"`
+	Present         bool          `json:"present"`
+	Up              bool          `json:"up"`
+	Required        bool          `json:"required"`
+	RxBytesPerSec   float64       `json:"rx_bytes_per_sec"`
+	TxBytesPerSec   float64       `json:"tx_bytes_per_sec"`
+	RxPacketsPerSec float64       `json:"rx_packets_per_sec"`
+	TxPacketsPerSec float64       `json:"tx_packets_per_sec"`
+	ErrorsPerSec    float64       `json:"errors_per_sec"`
+	DropsPerSec     float64       `json:"drops_per_sec"`
+	Status          models.Status `json:"status"`
+}
+
+func (c *NetworkCheck) Name() string {
+	return "network"
+}
+
+// Collect reads the interface list for link state and samples
+// net.IOCounters twice, Options.Interval apart, for rates. With a zero
+// interval the rates are averages since boot.
+func (c *NetworkCheck) Collect(ctx context.Context) error {
+	// - Call net.InterfacesWithContext(ctx) to get link state per interface
+	ifaces, err := net.InterfacesWithContext(ctx)
+	// - IF error THEN return error
+	if err != nil {
+		return err
+	}
+	// - Call net.IOCountersWithContext(ctx, true) to get per-interface
+	//   counters, twice Options.Interval apart
+	before, after, window, err := sampleWindow(ctx, time.Duration(c.Options.Interval), func() ([]net.IOCountersStat, error) {
+		return net.IOCountersWithContext(ctx, true)
+	})
+	if err != nil {
+		return err
+	}
+
+	// - Without an interval compare against zero over the uptime, i.e.
+	//   since boot
+	if c.Options.Interval <= 0 {
+		uptime, err := host.UptimeWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		window = time.Duration(uptime) * time.Second
+	}
+	rates := NetInterfaceRates(before, after, window, c.Options.Interval > 0)
+
+	// - FOR EACH interface that is selected or required: record link
+	//   state and rates
+	//   (an interface without rates, e.g. a veth created during the window,
+	//   is skipped unless required, which only needs its link state)
+	c.interfaces = make([]*models.NetInterfaceInfo, 0, len(ifaces))
+	seen := make(map[string]bool)
+	for _, iface := range ifaces {
+		required := slices.Contains(c.Options.RequiredInterfaces, iface.Name)
+		if !required && !c.Options.Selects(iface.Name) {
+			continue
+		}
+		info, ok := rates[iface.Name]
+		if !ok && !required {
+			continue
+		}
+		if !ok {
+			info = &models.NetInterfaceInfo{Name: iface.Name}
+		}
+		info.Present = true
+		info.Up = LinkUp(c.SysRoot, iface.Name, slices.Contains(iface.Flags, "up"))
+		info.Required = required
+		c.interfaces = append(c.interfaces, info)
+		seen[iface.Name] = true
+	}
+	// - FOR EACH required interface that does not exist: record it as missing
+	for _, name := range c.Options.RequiredInterfaces {
+		if !seen[name] {
+			c.interfaces = append(c.interfaces, &models.NetInterfaceInfo{Name: name, Required: true})
+			seen[name] = true
+		}
+	}
+	// - Return nil
+	return nil
+}
+
+// Evaluate flags missing or down required interfaces and compares error
+// and drop rates against the network thresholds
+func (c *NetworkCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.Network = c.interfaces
+
+	result := models.NewCheckResult(c.Name())
+	interfaces := make([]NetworkResult, 0, len(metrics.Network))
+	for _, n := range metrics.Network {
+		status := n.GetStatus(thresholds)
+		result.Status = result.Status.Worse(status)
+
+		var value string
+		switch {
+		case !n.Present:
+			value = "missing"
+		case !n.Up:
+			value = "down"
+		default:
+			value = fmt.Sprintf("up, rx %s/s tx %s/s, %.1f err/s, %.1f drop/s",
+				models.ByteSize(n.RxBytesPerSec), models.ByteSize(n.TxBytesPerSec), n.ErrorsPerSec, n.DropsPerSec)
+		}
+		threshold := fmt.Sprintf("< %.0f err/s, < %.0f drop/s", thresholds.NetErrorWarning, thresholds.NetDropWarning)
+		if n.Required {
+			threshold = "up, " + threshold
+		}
+		result.AddRow(fmt.Sprintf("Network %s", n.Name), value, status, threshold)
+
+		interfaces = append(interfaces, NetworkResult{
+			Name:            n.Name,
+			Present:         n.Present,
+			Up:              n.Up,
+			Required:        n.Required,
+			RxBytesPerSec:   n.RxBytesPerSec,
+			TxBytesPerSec:   n.TxBytesPerSec,
+			RxPacketsPerSec: n.RxPacketsPerSec,
+			TxPacketsPerSec: n.TxPacketsPerSec,
+			ErrorsPerSec:    n.ErrorsPerSec,
+			DropsPerSec:     n.DropsPerSec,
+			Status:          status,
+		})
+	}
+	result.Data = interfaces
+	return result
+}
+
+// LinkUp reports whether an interface has a working link, from
+// <sys root>/class/net/<name>/operstate. The admin "up" flag alone does
+// not say whether there is carrier. When operstate is "unknown" (e.g.
+// loopback and tunnels) or cannot be read, adminUp is used instead.
+func LinkUp(sysRoot, name string, adminUp bool) bool {
+	state, err := readSysString(sysPath(sysRoot, "class", "net", name, "operstate"))
+	if err != nil || state == "unknown" {
+		return adminUp
+	}
+	return state == "up"
+}
+
+// NetInterfaceRates turns two per-interface counter samples into rates,
+// keyed by interface name. When sampled is set the samples were taken
+// window apart, and interfaces missing from before are left out: they
+// appeared during the window and their since-boot counters are no rate.
+func NetInterfaceRates(before, after []net.IOCountersStat, window time.Duration, sampled bool) map[string]*models.NetInterfaceInfo {
+	previous := make(map[string]net.IOCountersStat, len(before))
+	for _, b := range before {
+		previous[b.Name] = b
+	}
+	rates := make(map[string]*models.NetInterfaceInfo, len(after))
+	for _, a := range after {
+		b, ok := previous[a.Name]
+		if sampled && !ok {
+			continue
+		}
+		rates[a.Name] = NetRates(a.Name, b, a, window)
+	}
+	return rates
+}
+
+// NetRates turns two counter samples taken window apart into rates
+func NetRates(name string, before, after net.IOCountersStat, window time.Duration) *models.NetInterfaceInfo {
+	return &models.NetInterfaceInfo{
+		Name:            name,
+		RxBytesPerSec:   counterRate(before.BytesRecv, after.BytesRecv, window),
+		TxBytesPerSec:   counterRate(before.BytesSent, after.BytesSent, window),
+		RxPacketsPerSec: counterRate(before.PacketsRecv, after.PacketsRecv, window),
+		TxPacketsPerSec: counterRate(before.PacketsSent, after.PacketsSent, window),
+		ErrorsPerSec:    counterRate(before.Errin+before.Errout, after.Errin+after.Errout, window),
+		DropsPerSec:     counterRate(before.Dropin+before.Dropout, after.Dropin+after.Dropout, window),
+	}
+}
@@ -0,0 +1,192 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// Options configures how the built-in checks collect data.
+// The json tags define the keys used in configuration files.
+type Options struct {
+	// ProcRoot is where procfs is mounted; checks that read /proc
+	// directly use it so they can be pointed at fixture files
+	ProcRoot string `json:"proc_root"`
+	// SysRoot is where sysfs is mounted, used the same way as ProcRoot
+	SysRoot string         `json:"sys_root"`
+	CPU     CPUOptions     `json:"cpu"`
+	Memory  MemoryOptions  `json:"memory"`
+	Disk    DiskOptions    `json:"disk"`
+	DiskIO  DiskIOOptions  `json:"diskio"`
+	Network NetworkOptions `json:"network"`
+	TCP     TCPOptions     `json:"tcp"`
+	Ports   PortOptions    `json:"ports"`
+	// Processes are the processes that must be running
+	Processes []models.ProcessSpec `json:"processes"`
+	Process   ProcessOptions       `json:"process"`
+}
+
+// CPUOptions configures CPU sampling
+type CPUOptions struct {
+	// Interval is the sampling window; zero means averages since boot
+	Interval models.Duration `json:"interval"`
+}
+
+// MemoryOptions configures swap activity sampling
+type MemoryOptions struct {
+	// SwapInterval is the window for swap-in/out rates; zero disables them
+	SwapInterval models.Duration `json:"swap_interval"`
+}
+
+// DiskOptions selects which partitions the disk check looks at and which
+// mounts the mounts check verifies. Include, exclude and writable entries
+// are patterns for models.MatchPattern; an empty include list includes
+// everything and exclude rules win over include rules.
+type DiskOptions struct {
+	IncludeMounts  []string `json:"include_mounts"`
+	ExcludeMounts  []string `json:"exclude_mounts"`
+	IncludeFSTypes []string `json:"include_fstypes"`
+	ExcludeFSTypes []string `json:"exclude_fstypes"`
+	IncludeDevices []string `json:"include_devices"`
+	ExcludeDevices []string `json:"exclude_devices"`
+	// RequiredMounts are mount points that must be present
+	RequiredMounts []string `json:"required_mounts"`
+	// WritableMounts are patterns for mounts that must not be read-only.
+	// The defaults cover the usual data mounts so a filesystem the kernel
+	// remounts read-only after an error is caught without configuration;
+	// an empty list disables the check.
+	WritableMounts []string `json:"writable_mounts"`
+}
+
+// DiskIOOptions configures disk I/O sampling and which block devices are
+// reported. Device entries are patterns for models.MatchPattern matched
+// against kernel device names such as "sda" or "nvme0n1".
+type DiskIOOptions struct {
+	// Interval is the sampling window; zero means averages since boot
+	Interval       models.Duration `json:"interval"`
+	IncludeDevices []string        `json:"include_devices"`
+	ExcludeDevices []string        `json:"exclude_devices"`
+	// IncludePartitions also reports partitions, not just whole disks
+	IncludePartitions bool `json:"include_partitions"`
+}
+
+// Selects reports whether a block device passes the include/exclude rules
+func (o *DiskIOOptions) Selects(device string) bool {
+	return selected(device, o.IncludeDevices, o.ExcludeDevices)
+}
+
+// NetworkOptions configures network sampling and which interfaces are
+// reported. Interface entries are patterns for models.MatchPattern.
+type NetworkOptions struct {
+	// Interval is the sampling window; zero means averages since boot
+	Interval          models.Duration `json:"interval"`
+	IncludeInterfaces []string        `json:"include_interfaces"`
+	ExcludeInterfaces []string        `json:"exclude_interfaces"`
+	// RequiredInterfaces must exist and be up; they are always reported
+	RequiredInterfaces []string `json:"required_interfaces"`
+}
+
+// Selects reports whether an interface passes the include/exclude rules
+func (o *NetworkOptions) Selects(name string) bool {
+	return selected(name, o.IncludeInterfaces, o.ExcludeInterfaces)
+}
+
+// TCPOptions configures TCP counter sampling
+type TCPOptions struct {
+	// Interval is the window for listen overflow rates; zero means
+	// averages since boot
+	Interval models.Duration `json:"interval"`
+}
+
+// ProcessOptions configures process CPU and I/O sampling and restart
+// tracking
+type ProcessOptions struct {
+	// Interval is the sampling window shared by all matched processes;
+	// zero means averages over each process's lifetime
+	Interval models.Duration `json:"interval"`
+	// StateFile persists observed process start times between runs;
+	// restarts are only tracked when it is set
+	StateFile string `json:"state_file"`
+	// RestartWindow is how far back restarts are counted
+	RestartWindow models.Duration `json:"restart_window"`
+}
+
+// PortOptions lists TCP listen assertions. Entries are "port",
+// "host:port" or "[ipv6]:port"; a bare port matches any local address.
+type PortOptions struct {
+	// Listening addresses must have a listener; a wildcard listener
+	// (0.0.0.0 or ::) on the same port also satisfies a specific address
+	Listening []string `json:"listening"`
+	// NotListening addresses must have no listener that serves them,
+	// including a wildcard listener (:: also serves IPv4) on the same port
+	NotListening []string `json:"not_listening"`
+}
+
+// Validate checks options that cannot be checked while decoding
+func (o *Options) Validate() error {
+	return errors.Join(o.Ports.Validate(), validateProcessSpecs(o.Processes), o.Process.Validate())
+}
+
+// Validate checks that restart tracking has a window to count restarts in
+func (o *ProcessOptions) Validate() error {
+	if o.StateFile != "" && o.RestartWindow <= 0 {
+		return fmt.Errorf("restart window must be positive when a process state file is set, got %s", o.RestartWindow)
+	}
+	return nil
+}
+
+// Validate checks that every listen assertion can be parsed
+func (o *PortOptions) Validate() error {
+	return errors.Join(validateListenAddresses(o.Listening), validateListenAddresses(o.NotListening))
+}
+
+func NewDefaultOptions() *Options {
+	return &Options{
+		ProcRoot: DefaultProcRoot,
+		SysRoot:  DefaultSysRoot,
+		CPU:      CPUOptions{Interval: models.Duration(DefaultCPUInterval)},
+		Memory:   MemoryOptions{SwapInterval: models.Duration(DefaultSwapInterval)},
+		Disk: DiskOptions{
+			WritableMounts: []string{"/", "/home", "/home/**", "/srv", "/srv/**", "/var", "/var/**", "/data", "/data/**"},
+		},
+		DiskIO: DiskIOOptions{
+			Interval:       models.Duration(DefaultDiskIOInterval),
+			ExcludeDevices: []string{"loop*", "ram*"},
+		},
+		Network: NetworkOptions{
+			Interval:          models.Duration(DefaultNetworkInterval),
+			ExcludeInterfaces: []string{"lo"},
+		},
+		TCP: TCPOptions{Interval: models.Duration(DefaultTCPInterval)},
+		Process: ProcessOptions{
+			Interval:      models.Duration(DefaultProcessInterval),
+			RestartWindow: models.Duration(DefaultRestartWindow),
+		},
+	}
+}
+
+// Selects reports whether a partition passes the include/exclude rules
+func (o *DiskOptions) Selects(mountPoint, fsType, device string) bool {
+	return selected(mountPoint, o.IncludeMounts, o.ExcludeMounts) &&
+		selected(fsType, o.IncludeFSTypes, o.ExcludeFSTypes) &&
+		selected(device, o.IncludeDevices, o.ExcludeDevices)
+}
+
+// selected applies one include/exclude pair of glob lists to value
+func selected(value string, include, exclude []string) bool {
+	if matchAny(value, exclude) {
+		return false
+	}
+	return len(include) == 0 || matchAny(value, include)
+}
+
+// matchAny reports whether value matches any of the patterns
+func matchAny(value string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if models.MatchPattern(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
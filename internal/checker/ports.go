@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+	"github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// PortCheck verifies that required TCP addresses are listening and that
+// forbidden ones are not, using the local socket table
+type PortCheck struct {
+	Options    PortOptions
+	assertions []*models.PortAssertion
+}
+
+// PortResult is the typed result of the ports check for one assertion
+type PortResult struct {
+	Address   string               `json:"address"`
+	Expect    string               `json:"expect"`
+	Listening bool                 `json:"listening"`
+	Listeners []PortListenerResult `json:"listeners"`
+	Status    models.Status        `json:"status"`
+}
+
+// PortListenerResult is one listening socket matched by an assertion
+type PortListenerResult struct {
+	Address string `json:"address"`
+	PID     int32  `json:"pid,omitempty"`
+	Process string `json:"process,omitempty"`
+}
+
+func (c *PortCheck) Name() string {
+	return "ports"
+}
+
+// Collect reads the listening TCP sockets and matches them against the
+// configured assertions
+func (c *PortCheck) Collect(ctx context.Context) error {
+	c.assertions = make([]*models.PortAssertion, 0)
+	// - IF no assertions are configured THEN nothing to do
+	if len(c.Options.Listening) == 0 && len(c.Options.NotListening) == 0 {
+		return nil
+	}
+
+	// - Call net.ConnectionsWithContext(ctx, "tcp") to get IPv4 and IPv6 sockets
+	conns, err := net.ConnectionsWithContext(ctx, "tcp")
+	if err != nil {
+		return err
+	}
+	listeners := make([]models.PortListener, 0)
+	names := make(map[int32]string)
+	for _, conn := range conns {
+		if conn.Status != "LISTEN" {
+			continue
+		}
+		listener := models.PortListener{Host: conn.Laddr.IP, Port: conn.Laddr.Port, PID: conn.Pid}
+		// - Look up the owning process; the PID is only known where permitted
+		if conn.Pid > 0 {
+			if _, ok := names[conn.Pid]; !ok {
+				names[conn.Pid] = processName(ctx, conn.Pid)
+			}
+			listener.Process = names[conn.Pid]
+		}
+		listeners = append(listeners, listener)
+	}
+
+	// - FOR EACH assertion: any matching or wildcard listener that serves
+	//   the address meets a required one and violates a forbidden one
+	for _, spec := range c.Options.Listening {
+		address, err := models.ParseListenAddress(spec)
+		if err != nil {
+			return err
+		}
+		assertion := &models.PortAssertion{Address: address, Expect: true}
+		for _, l := range listeners {
+			if address.ServedBy(l.Host, l.Port) {
+				assertion.Listeners = append(assertion.Listeners, l)
+			}
+		}
+		c.assertions = append(c.assertions, assertion)
+	}
+	for _, spec := range c.Options.NotListening {
+		address, err := models.ParseListenAddress(spec)
+		if err != nil {
+			return err
+		}
+		assertion := &models.PortAssertion{Address: address, Expect: false}
+		for _, l := range listeners {
+			if address.ServedBy(l.Host, l.Port) {
+				assertion.Listeners = append(assertion.Listeners, l)
+			}
+		}
+		c.assertions = append(c.assertions, assertion)
+	}
+	return nil
+}
+
+// Evaluate flags every assertion that is not met as CRITICAL
+func (c *PortCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.Ports = c.assertions
+
+	result := models.NewCheckResult(c.Name())
+	ports := make([]PortResult, 0, len(metrics.Ports))
+	for _, a := range metrics.Ports {
+		status := a.GetStatus()
+		result.Status = result.Status.Worse(status)
+
+		expect, threshold := "listening", "must listen"
+		if !a.Expect {
+			expect, threshold = "not_listening", "must not listen"
+		}
+		value := "not listening"
+		owners := make([]string, 0, len(a.Listeners))
+		listeners := make([]PortListenerResult, 0, len(a.Listeners))
+		for _, l := range a.Listeners {
+			address := models.ListenAddress{Host: l.Host, Port: l.Port}.String()
+			owner := address
+			if l.Process != "" {
+				owner = fmt.Sprintf("%s (%s, pid %d)", address, l.Process, l.PID)
+			}
+			owners = append(owners, owner)
+			listeners = append(listeners, PortListenerResult{Address: address, PID: l.PID, Process: l.Process})
+		}
+		if len(owners) > 0 {
+			value = "listening on " + strings.Join(owners, ", ")
+		}
+		result.AddRow(fmt.Sprintf("Port %s", a.Address), value, status, threshold)
+
+		ports = append(ports, PortResult{
+			Address:   a.Address.String(),
+			Expect:    expect,
+			Listening: a.Listening(),
+			Listeners: listeners,
+			Status:    status,
+		})
+	}
+	result.Data = ports
+	return result
+}
+
+// processName returns the name of a process, or "" if it cannot be read
+func processName(ctx context.Context, pid int32) string {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return ""
+	}
+	name, err := p.NameWithContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// validateListenAddresses parses every entry so that typos are reported
+// before any check runs
+func validateListenAddresses(specs []string) error {
+	var errs []error
+	for _, spec := range specs {
+		if _, err := models.ParseListenAddress(spec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,193 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// pressureResources are the PSI files read from <proc root>/pressure
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// PressureCheck reports Linux pressure stall information (PSI) for CPU,
+// memory and I/O. Kernels without PSI report the check as not supported.
+type PressureCheck struct {
+	// ProcRoot is where procfs is mounted; empty means DefaultProcRoot
+	ProcRoot  string
+	pressure  []*models.PressureInfo
+	supported bool
+}
+
+// PressureResult is the typed result of the pressure check for one resource
+type PressureResult struct {
+	Resource string              `json:"resource"`
+	Some     PressureStatResult  `json:"some"`
+	Full     *PressureStatResult `json:"full,omitempty"`
+	Status   models.Status       `json:"status"`
+}
+
+// PressureStatResult is one PSI line in percent stalled
+type PressureStatResult struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	// Total is the cumulative stall time in microseconds
+	Total uint64 `json:"total_us"`
+}
+
+func (c *PressureCheck) Name() string {
+	return "pressure"
+}
+
+// Collect reads <proc root>/pressure/{cpu,memory,io}
+func (c *PressureCheck) Collect(ctx context.Context) error {
+	c.pressure = make([]*models.PressureInfo, 0, len(pressureResources))
+	c.supported = false
+	// - FOR EACH resource:
+	//     - IF its file does not exist THEN skip it (PSI disabled or old kernel)
+	//     - Parse the "some" and "full" lines
+	for _, resource := range pressureResources {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		info, err := readPressure(procPath(c.ProcRoot, "pressure", resource), resource)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		c.pressure = append(c.pressure, info)
+		c.supported = true
+	}
+	return nil
+}
+
+// Evaluate compares each resource's "some" and "full" 10 second averages
+// against the pressure thresholds for that resource
+func (c *PressureCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.Pressure = c.pressure
+
+	result := models.NewCheckResult(c.Name())
+	pressure := make([]PressureResult, 0, len(metrics.Pressure))
+	if !c.supported {
+		result.AddRow("Pressure", "not supported by this kernel", models.StatusOK, "")
+	}
+	for _, p := range metrics.Pressure {
+		status := p.GetStatus(thresholds)
+		result.Status = result.Status.Worse(status)
+
+		value := fmt.Sprintf("some %.2f%% / %.2f%% / %.2f%%", p.Some.Avg10, p.Some.Avg60, p.Some.Avg300)
+		if p.Full != nil {
+			value += fmt.Sprintf(", full %.2f%% / %.2f%% / %.2f%%", p.Full.Avg10, p.Full.Avg60, p.Full.Avg300)
+		}
+		warning, _ := thresholds.ForPressure(p.Resource)
+		threshold := fmt.Sprintf("some avg10 < %.0f%%", warning)
+		if fullWarning, _ := thresholds.ForPressureFull(p.Resource); p.Full != nil && !math.IsInf(fullWarning, 1) {
+			threshold += fmt.Sprintf(", full avg10 < %.0f%%", fullWarning)
+		}
+		result.AddRow(
+			fmt.Sprintf("Pressure %s", p.Resource),
+			value,
+			status,
+			threshold,
+		)
+
+		entry := PressureResult{
+			Resource: p.Resource,
+			Some:     pressureStatResult(p.Some),
+			Status:   status,
+		}
+		if p.Full != nil {
+			full := pressureStatResult(*p.Full)
+			entry.Full = &full
+		}
+		pressure = append(pressure, entry)
+	}
+	result.Data = pressure
+	return result
+}
+
+func pressureStatResult(s models.PressureStat) PressureStatResult {
+	return PressureStatResult{
+		Avg10:  s.Avg10,
+		Avg60:  s.Avg60,
+		Avg300: s.Avg300,
+		Total:  s.Total,
+	}
+}
+
+// readPressure parses one PSI file
+func readPressure(path, resource string) (*models.PressureInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := parsePressure(f, resource)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return info, nil
+}
+
+// parsePressure parses lines of the form
+//
+//	some avg10=0.12 avg60=0.05 avg300=0.01 total=12345
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(r io.Reader, resource string) (*models.PressureInfo, error) {
+	info := &models.PressureInfo{Resource: resource}
+	hasSome := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var stat models.PressureStat
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return nil, fmt.Errorf("malformed field %q", field)
+			}
+			var err error
+			switch key {
+			case "avg10":
+				stat.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stat.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stat.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				stat.Total, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", field, err)
+			}
+		}
+		switch fields[0] {
+		case "some":
+			info.Some = stat
+			hasSome = true
+		case "full":
+			info.Full = &stat
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !hasSome {
+		return nil, fmt.Errorf("missing \"some\" line")
+	}
+	return info, nil
+}
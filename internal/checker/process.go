@@ -1,62 +1,525 @@
 package checker
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/andinianst93/system-health-checker/internal/models"
 	"github.com/shirou/gopsutil/v4/process"
 )
 
-// CheckProcess checks if a specific process is running
-func (hc *HealthChecker) CheckProcess(processName string) error {
-	// - Call process.Processes() to get all processes
-	processes, err := process.Processes()
+// DefaultProcessInterval is the process CPU and I/O sampling window used
+// when none is configured
+const DefaultProcessInterval = time.Second
+
+// ProcessCheck counts the running instances of every configured process
+// spec and records each matching instance
+type ProcessCheck struct {
+	Specs   []models.ProcessSpec
+	Options ProcessOptions
+	matches [][]*models.ProcessInfo
+	errs    []error
+	// restarts holds the restart times per spec within the window; nil
+	// when restarts are not tracked
+	restarts [][]time.Time
+	stateErr error
+}
+
+// ProcessResult is the typed result of the processes check for one spec
+type ProcessResult struct {
+	Name      string                  `json:"name"`
+	Cmdline   string                  `json:"cmdline,omitempty"`
+	User      string                  `json:"user,omitempty"`
+	Parent    string                  `json:"parent,omitempty"`
+	Pidfile   string                  `json:"pidfile,omitempty"`
+	Min       int                     `json:"min"`
+	Max       int                     `json:"max,omitempty"`
+	Count     int                     `json:"count"`
+	Instances []ProcessInstanceResult `json:"instances"`
+	Restarts  *ProcessRestartResult   `json:"restarts,omitempty"`
+	Error     string                  `json:"error,omitempty"`
+	Status    models.Status           `json:"status"`
+}
+
+// ProcessRestartResult counts the restarts of a process within the window
+type ProcessRestartResult struct {
+	Count       int             `json:"count"`
+	Window      models.Duration `json:"window"`
+	LastRestart *time.Time      `json:"last_restart,omitempty"`
+	Status      models.Status   `json:"status"`
+}
+
+// ProcessInstanceResult is one running instance of a process
+type ProcessInstanceResult struct {
+	PID              int32         `json:"pid"`
+	Name             string        `json:"name"`
+	Cmdline          string        `json:"cmdline"`
+	User             string        `json:"user"`
+	State            string        `json:"state"`
+	StartTime        time.Time     `json:"start_time"`
+	UptimeSeconds    float64       `json:"uptime_seconds"`
+	CPUPercent       float64       `json:"cpu_percent"`
+	MemoryPercent    float64       `json:"memory_percent"`
+	RSSBytes         uint64        `json:"rss_bytes"`
+	NumFDs           int32         `json:"num_fds"`
+	NumThreads       int32         `json:"num_threads"`
+	ReadBytesPerSec  float64       `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64       `json:"write_bytes_per_sec"`
+	Status           models.Status `json:"status"`
+}
+
+// processMatcher is a ProcessSpec prepared for matching
+type processMatcher struct {
+	spec    models.ProcessSpec
+	cmdline *regexp.Regexp
+	// pid is the pid read from the pidfile, zero when there is none
+	pid int32
+}
+
+// processSample is a reading of the cumulative counters of one process
+type processSample struct {
+	at         time.Time
+	cpuSeconds float64
+	readBytes  uint64
+	writeBytes uint64
+	hasCPU     bool
+	hasIO      bool
+}
+
+func (c *ProcessCheck) Name() string {
+	return "processes"
+}
+
+// Collect scans all processes once and records every instance that
+// matches a spec
+func (c *ProcessCheck) Collect(ctx context.Context) error {
+	c.matches = make([][]*models.ProcessInfo, len(c.Specs))
+	c.errs = make([]error, len(c.Specs))
+	c.restarts, c.stateErr = nil, nil
+	// - IF no process specs are configured THEN nothing to do
+	if len(c.Specs) == 0 {
+		return nil
+	}
+
+	// - Prepare a matcher per spec; a spec whose pidfile cannot be read
+	//   matches nothing and reports why
+	matchers := make([]*processMatcher, len(c.Specs))
+	for i, spec := range c.Specs {
+		matchers[i], c.errs[i] = newProcessMatcher(spec)
+	}
+
+	// - Call process.ProcessesWithContext(ctx) to get all processes
+	processes, err := process.ProcessesWithContext(ctx)
 	// - IF error THEN return error
 	if err != nil {
 		return err
 	}
 
-	// - FOR EACH proc IN processes:
-	//     - Call proc.Name() to get process name
-	//     - IF error THEN continue
-	//     - IF name matches processName THEN
-	//         - Get proc.Pid()
-	//         - Get proc.CPUPercent()
-	//         - Get proc.MemoryPercent()
-	//         - Create processInfo = models.NewProcessInfo(pid, name)
-	//         - Set processInfo.CPUPercent
-	//         - Set processInfo.MemoryPercent
-	//         - Set processInfo.Status = "running"
-	//         - Append to hc.metrics.Processes
-	//         - Return nil (found)
+	// - Get every process name up front, parents are matched by name;
+	//   skip processes that exited or cannot be read
+	names := make(map[int32]string, len(processes))
+	for _, proc := range processes {
+		if name, err := proc.NameWithContext(ctx); err == nil {
+			names[proc.Pid] = name
+		}
+	}
 
+	// - FOR EACH proc IN processes:
+	//     - FOR EACH spec whose matchers all match: record the instance
+	var matched []*process.Process
+	var infos []*models.ProcessInfo
 	for _, proc := range processes {
-		name, err := proc.Name()
-		if err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name, ok := names[proc.Pid]
+		if !ok {
 			continue
 		}
-		if name == processName {
-			pid, err := proc.Ppid()
-			if err != nil {
+		var info *models.ProcessInfo
+		for i, m := range matchers {
+			if m == nil || !m.matches(ctx, proc, name, names) {
 				continue
 			}
-			cpuPercent, err := proc.CPUPercent()
-			if err != nil {
-				continue
+			if info == nil {
+				info = processInfo(ctx, proc, name)
+				matched = append(matched, proc)
+				infos = append(infos, info)
 			}
-			memPercent, err := proc.MemoryPercent()
-			if err != nil {
-				continue
+			c.matches[i] = append(c.matches[i], info)
+		}
+	}
+
+	// - IF a pidfile names a pid that is gone or does not match THEN say so
+	for i, m := range matchers {
+		if m != nil && m.pid != 0 && len(c.matches[i]) == 0 {
+			c.errs[i] = fmt.Errorf("pid %d from %s is not running or does not match", m.pid, m.spec.Pidfile)
+		}
+	}
+
+	// - Measure CPU and I/O of all matched processes over one window
+	if err := c.sampleUsage(ctx, matched, infos); err != nil {
+		return err
+	}
+
+	// - IF a state file is configured THEN count restarts since earlier runs
+	if c.Options.StateFile != "" {
+		c.trackRestarts(time.Now())
+	}
+	return nil
+}
+
+// trackRestarts compares the matched instances with the state file and
+// writes the updated state back. A state file that cannot be read is
+// replaced, and restarts are not reported for that run.
+func (c *ProcessCheck) trackRestarts(now time.Time) {
+	state, loadErr := loadProcessState(c.Options.StateFile)
+	window := time.Duration(c.Options.RestartWindow)
+	next := &processState{Specs: make(map[string]*processSpecState, len(c.Specs))}
+	restarts := make([][]time.Time, len(c.Specs))
+	for i, spec := range c.Specs {
+		key := spec.Key()
+		specState := trackRestarts(state.Specs[key], c.matches[i], now, window)
+		next.Specs[key] = specState
+		restarts[i] = specState.Restarts
+	}
+	if loadErr == nil {
+		c.restarts = restarts
+	}
+	saveErr := saveProcessState(c.Options.StateFile, next)
+	if err := errors.Join(loadErr, saveErr); err != nil {
+		c.stateErr = fmt.Errorf("process state: %w", err)
+	}
+}
+
+// sampleUsage sets the CPU and I/O rates of the matched processes. All
+// processes are sampled, the window is waited out once, and all are
+// sampled again. Without a window, or for a process that exits during
+// it, the rates are averages over the process lifetime.
+func (c *ProcessCheck) sampleUsage(ctx context.Context, procs []*process.Process, infos []*models.ProcessInfo) error {
+	if len(procs) == 0 {
+		return nil
+	}
+	// - Sample every process, twice Options.Interval apart
+	before, after, _, err := sampleWindow(ctx, time.Duration(c.Options.Interval), func() ([]processSample, error) {
+		samples := make([]processSample, len(procs))
+		for i, proc := range procs {
+			samples[i] = sampleProcess(ctx, proc)
+		}
+		return samples, nil
+	})
+	if err != nil {
+		return err
+	}
+	// - Derive lifetime averages from the first sample, then replace them
+	//   with the window rates where the second sample could be read
+	first := before
+	if first == nil {
+		first = after
+	}
+	for i := range procs {
+		setUsage(infos[i], processSample{at: infos[i].StartTime, hasCPU: true, hasIO: true}, first[i])
+		if before != nil {
+			setUsage(infos[i], before[i], after[i])
+		}
+	}
+	return nil
+}
+
+// sampleProcess reads the CPU time and I/O counters of a process
+func sampleProcess(ctx context.Context, proc *process.Process) processSample {
+	sample := processSample{at: time.Now()}
+	if times, err := proc.TimesWithContext(ctx); err == nil {
+		sample.cpuSeconds = times.User + times.System
+		sample.hasCPU = true
+	}
+	if io, err := proc.IOCountersWithContext(ctx); err == nil {
+		sample.readBytes = io.ReadBytes
+		sample.writeBytes = io.WriteBytes
+		sample.hasIO = true
+	}
+	return sample
+}
+
+// setUsage sets the CPU percent and I/O rates of a process from the
+// counter deltas between two samples; counters missing from either
+// sample leave the previous values
+func setUsage(info *models.ProcessInfo, before, after processSample) {
+	elapsed := after.at.Sub(before.at)
+	if before.at.IsZero() || elapsed <= 0 {
+		return
+	}
+	if before.hasCPU && after.hasCPU && after.cpuSeconds >= before.cpuSeconds {
+		info.CPUPercent = (after.cpuSeconds - before.cpuSeconds) / elapsed.Seconds() * 100
+	}
+	if before.hasIO && after.hasIO {
+		info.ReadBytesPerSec = counterRate(before.readBytes, after.readBytes, elapsed)
+		info.WriteBytesPerSec = counterRate(before.writeBytes, after.writeBytes, elapsed)
+	}
+}
+
+// Evaluate compares the instance count of every spec against its
+// expected range, and every instance against the spec's limits; a
+// missing process is CRITICAL
+func (c *ProcessCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	result := models.NewCheckResult(c.Name())
+	specs := make([]ProcessResult, 0, len(c.Specs))
+	seen := make(map[int32]bool)
+	for i, spec := range c.Specs {
+		matches := c.matches[i]
+		countStatus := spec.GetStatus(len(matches))
+		status := countStatus
+
+		value := "not running"
+		if len(matches) > 0 {
+			value = fmt.Sprintf("%d running (pid %s)", len(matches), formatPIDs(matches))
+		}
+		errText := ""
+		if c.errs[i] != nil {
+			errText = c.errs[i].Error()
+			value = fmt.Sprintf("%s (%s)", value, errText)
+		}
+		result.AddRow(fmt.Sprintf("Process %s", spec.Label()), value, countStatus, spec.Expected())
+
+		// - One row per instance; past maxListedPIDs only instances over
+		//   a limit are listed
+		instances := make([]ProcessInstanceResult, 0, len(matches))
+		listed := 0
+		for _, p := range matches {
+			if !seen[p.PID] {
+				metrics.Processes = append(metrics.Processes, p)
+				seen[p.PID] = true
+			}
+			instanceStatus := spec.ProcessLimits.Evaluate(p)
+			status = status.Worse(instanceStatus)
+			if instanceStatus != models.StatusOK || listed < maxListedPIDs {
+				result.AddRow(fmt.Sprintf("%s[%d]", p.Name, p.PID), formatProcessUsage(p), instanceStatus, spec.Describe())
+				listed++
+			}
+			instances = append(instances, ProcessInstanceResult{
+				PID:              p.PID,
+				Name:             p.Name,
+				Cmdline:          p.Cmdline,
+				User:             p.Username,
+				State:            p.Status,
+				StartTime:        p.StartTime,
+				UptimeSeconds:    p.Uptime.Seconds(),
+				CPUPercent:       p.CPUPercent,
+				MemoryPercent:    p.MemoryPercent,
+				RSSBytes:         p.RSSBytes,
+				NumFDs:           p.NumFDs,
+				NumThreads:       p.NumThreads,
+				ReadBytesPerSec:  p.ReadBytesPerSec,
+				WriteBytesPerSec: p.WriteBytesPerSec,
+				Status:           instanceStatus,
+			})
+		}
+
+		// - Restarts within the window, when tracked
+		var restartResult *ProcessRestartResult
+		if c.restarts != nil {
+			restartResult = restartSummary(c.restarts[i], c.Options.RestartWindow, thresholds)
+			status = status.Worse(restartResult.Status)
+			value := fmt.Sprintf("%d in %s", restartResult.Count, time.Duration(restartResult.Window))
+			if restartResult.LastRestart != nil {
+				value += fmt.Sprintf(", last %s", restartResult.LastRestart.Local().Format("2006-01-02 15:04:05"))
 			}
-			processInfo := models.NewProcessInfo(pid, name)
-			processInfo.CPUPercent = cpuPercent
-			processInfo.MemoryPercent = float64(memPercent)
-			processInfo.Status = "running"
-			hc.metrics.Processes = append(hc.metrics.Processes, processInfo)
-			return nil
+			result.AddRow(fmt.Sprintf("Restarts %s", spec.Label()), value, restartResult.Status,
+				fmt.Sprintf("< %.0f per %s", thresholds.RestartWarning, time.Duration(restartResult.Window)))
+		}
+		result.Status = result.Status.Worse(status)
+
+		specs = append(specs, ProcessResult{
+			Name:      spec.Label(),
+			Cmdline:   spec.Cmdline,
+			User:      spec.User,
+			Parent:    spec.Parent,
+			Pidfile:   spec.Pidfile,
+			Min:       spec.Min,
+			Max:       spec.Max,
+			Count:     len(matches),
+			Instances: instances,
+			Restarts:  restartResult,
+			Error:     errText,
+			Status:    status,
+		})
+	}
+	// - A state file that cannot be read or written is UNKNOWN
+	if c.stateErr != nil {
+		result.Status = result.Status.Worse(models.StatusUnknown)
+		result.AddRow("Restart Tracking", c.stateErr.Error(), models.StatusUnknown, "")
+	}
+	result.Data = specs
+	return result
+}
+
+// restartSummary evaluates the restarts of one spec against the restart
+// thresholds
+func restartSummary(restarts []time.Time, window models.Duration, thresholds *models.Thresholds) *ProcessRestartResult {
+	summary := &ProcessRestartResult{
+		Count:  len(restarts),
+		Window: window,
+		Status: models.EvaluateHigher(float64(len(restarts)), thresholds.RestartWarning, thresholds.RestartCritical),
+	}
+	if len(restarts) > 0 {
+		last := restarts[len(restarts)-1]
+		summary.LastRestart = &last
+	}
+	return summary
+}
+
+// maxListedPIDs is how many pids a table row lists before eliding
+const maxListedPIDs = 5
+
+// formatPIDs lists the pids of the matched instances, eliding long lists
+func formatPIDs(matches []*models.ProcessInfo) string {
+	pids := make([]string, 0, maxListedPIDs+1)
+	for i, p := range matches {
+		if i == maxListedPIDs {
+			pids = append(pids, fmt.Sprintf("+%d more", len(matches)-i))
+			break
+		}
+		pids = append(pids, fmt.Sprint(p.PID))
+	}
+	return strings.Join(pids, ", ")
+}
+
+// formatProcessUsage summarizes the resource usage of one instance
+func formatProcessUsage(p *models.ProcessInfo) string {
+	return fmt.Sprintf("up %s, cpu %.1f%%, rss %s, fds %d, threads %d, io r %s/s w %s/s",
+		p.Uptime.Round(time.Second), p.CPUPercent, models.ByteSize(p.RSSBytes), p.NumFDs, p.NumThreads,
+		models.ByteSize(p.ReadBytesPerSec), models.ByteSize(p.WriteBytesPerSec))
+}
+
+// newProcessMatcher compiles the cmdline pattern and reads the pidfile
+// of a spec
+func newProcessMatcher(spec models.ProcessSpec) (*processMatcher, error) {
+	m := &processMatcher{spec: spec}
+	if spec.Cmdline != "" {
+		re, err := regexp.Compile(spec.Cmdline)
+		if err != nil {
+			return nil, err
+		}
+		m.cmdline = re
+	}
+	if spec.Pidfile != "" {
+		pid, err := readPidfile(spec.Pidfile)
+		if err != nil {
+			return nil, err
 		}
+		m.pid = pid
 	}
+	return m, nil
+}
 
-	// - Return error with message "process not found: %s"
-	return fmt.Errorf("process not found: %s", processName)
+// matches reports whether proc satisfies every matcher of the spec;
+// names maps pids to process names for parent matching
+func (m *processMatcher) matches(ctx context.Context, proc *process.Process, name string, names map[int32]string) bool {
+	if m.pid != 0 && proc.Pid != m.pid {
+		return false
+	}
+	if m.spec.Name != "" && name != m.spec.Name {
+		return false
+	}
+	if m.cmdline != nil {
+		cmdline, err := proc.CmdlineWithContext(ctx)
+		if err != nil || !m.cmdline.MatchString(cmdline) {
+			return false
+		}
+	}
+	if m.spec.User != "" && !matchesUser(ctx, proc, m.spec.User) {
+		return false
+	}
+	if m.spec.Parent != "" {
+		ppid, err := proc.PpidWithContext(ctx)
+		if err != nil {
+			return false
+		}
+		if pid, err := strconv.ParseInt(m.spec.Parent, 10, 32); err == nil {
+			return int32(pid) == ppid
+		}
+		return names[ppid] == m.spec.Parent
+	}
+	return true
+}
+
+// matchesUser compares the owner of proc with a user name or numeric uid
+func matchesUser(ctx context.Context, proc *process.Process, user string) bool {
+	if uid, err := strconv.ParseUint(user, 10, 32); err == nil {
+		uids, err := proc.UidsWithContext(ctx)
+		return err == nil && len(uids) > 0 && uint64(uids[0]) == uid
+	}
+	username, err := proc.UsernameWithContext(ctx)
+	return err == nil && username == user
+}
+
+// readPidfile reads the pid from the first line of a pidfile
+func readPidfile(path string) (int32, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	pid, err := strconv.ParseInt(strings.TrimSpace(line), 10, 32)
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("%s does not hold a valid pid", path)
+	}
+	return int32(pid), nil
+}
+
+// processInfo reads the details of one process; fields that cannot be
+// read (e.g. without permission) are left at their defaults. CPU and I/O
+// rates are set by sampleUsage.
+func processInfo(ctx context.Context, proc *process.Process, name string) *models.ProcessInfo {
+	info := models.NewProcessInfo(proc.Pid, name)
+	if cmdline, err := proc.CmdlineWithContext(ctx); err == nil {
+		info.Cmdline = cmdline
+	}
+	if username, err := proc.UsernameWithContext(ctx); err == nil {
+		info.Username = username
+	}
+	if created, err := proc.CreateTimeWithContext(ctx); err == nil {
+		info.StartTime = time.UnixMilli(created)
+		info.Uptime = time.Since(info.StartTime)
+	}
+	if memPercent, err := proc.MemoryPercentWithContext(ctx); err == nil {
+		info.MemoryPercent = float64(memPercent)
+	}
+	if memInfo, err := proc.MemoryInfoWithContext(ctx); err == nil {
+		info.RSSBytes = memInfo.RSS
+	}
+	if fds, err := proc.NumFDsWithContext(ctx); err == nil {
+		info.NumFDs = fds
+	}
+	if threads, err := proc.NumThreadsWithContext(ctx); err == nil {
+		info.NumThreads = threads
+	}
+	if status, err := proc.StatusWithContext(ctx); err == nil && len(status) > 0 {
+		info.Status = strings.Join(status, ",")
+	}
+	return info
+}
+
+// validateProcessSpecs checks every configured process spec
+func validateProcessSpecs(specs []models.ProcessSpec) error {
+	var errs []error
+	seen := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if err := spec.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+		// - Specs matching the same processes would share restart state
+		if key := spec.Key(); seen[key] {
+			errs = append(errs, fmt.Errorf("process %s is listed more than once", spec.Label()))
+		} else {
+			seen[key] = true
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,172 @@
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// DefaultRestartWindow is how far back process restarts are counted when
+// no window is configured
+const DefaultRestartWindow = time.Hour
+
+// startTimeTolerance absorbs jitter in process start times: they are
+// derived from a boot time in whole seconds, which in containers is
+// computed from the uptime, so the same process can read a second apart
+const startTimeTolerance = 2 * time.Second
+
+// processState is what the processes check keeps between runs, keyed by
+// models.ProcessSpec.Key
+type processState struct {
+	Specs map[string]*processSpecState `json:"specs"`
+}
+
+// processSpecState holds the instances of one spec seen at the last run
+// and the restarts observed within the window
+type processSpecState struct {
+	LastRun   time.Time      `json:"last_run"`
+	Instances []processStart `json:"instances"`
+	// Vanished holds when instances were found gone that no new instance
+	// has replaced yet
+	Vanished []time.Time `json:"vanished"`
+	Restarts []time.Time `json:"restarts"`
+}
+
+// processStart identifies one process instance across runs
+type processStart struct {
+	PID       int32     `json:"pid"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// loadProcessState reads the state file; a missing file is an empty state
+func loadProcessState(path string) (*processState, error) {
+	state := &processState{Specs: make(map[string]*processSpecState)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return &processState{Specs: make(map[string]*processSpecState)}, fmt.Errorf("%s: %w", path, err)
+	}
+	if state.Specs == nil {
+		state.Specs = make(map[string]*processSpecState)
+	}
+	return state, nil
+}
+
+// saveProcessState writes the state file, replacing it atomically so an
+// interrupted run cannot leave it truncated
+func saveProcessState(path string, state *processState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// trackRestarts returns the new state of one spec given the instances
+// running now. A restart is an instance that started since the previous
+// run replacing one that has gone, so scale-ups and added workers do not
+// count: per spec, min(new instances, vanished instances) restarts are
+// recorded at the new instances' start times. Instances that vanish
+// without a replacement stay pending within the window, so a crash seen
+// while the process is down still counts once it is back. The first run
+// of a spec only records its instances. Entries older than the window
+// are dropped.
+func trackRestarts(prev *processSpecState, instances []*models.ProcessInfo, now time.Time, window time.Duration) *processSpecState {
+	next := &processSpecState{
+		LastRun:   now,
+		Instances: make([]processStart, 0, len(instances)),
+		Vanished:  make([]time.Time, 0),
+		Restarts:  make([]time.Time, 0),
+	}
+	cutoff := now.Add(-window)
+	var vanished []time.Time
+	if prev != nil {
+		next.Restarts = append(next.Restarts, withinWindow(prev.Restarts, cutoff)...)
+		vanished = withinWindow(prev.Vanished, cutoff)
+	}
+
+	// - Record the running instances and which of them are new
+	var started []time.Time
+	for _, p := range instances {
+		// - Instances without a known start time cannot be followed
+		if p.StartTime.IsZero() {
+			continue
+		}
+		start := processStart{PID: p.PID, StartTime: p.StartTime}
+		next.Instances = append(next.Instances, start)
+		if prev == nil || containsStart(prev.Instances, start) || p.StartTime.Before(prev.LastRun.Add(-startTimeTolerance)) {
+			continue
+		}
+		started = append(started, p.StartTime)
+	}
+
+	// - Instances seen at the last run that are gone now have vanished
+	if prev != nil {
+		for _, start := range prev.Instances {
+			if !containsStart(next.Instances, start) {
+				vanished = append(vanished, now)
+			}
+		}
+	}
+
+	// - Each new instance replaces the oldest vanished one; new instances
+	//   beyond that are scale-ups
+	sort.Slice(started, func(i, j int) bool { return started[i].Before(started[j]) })
+	sort.Slice(vanished, func(i, j int) bool { return vanished[i].Before(vanished[j]) })
+	replaced := min(len(started), len(vanished))
+	for _, t := range started[:replaced] {
+		if t.After(cutoff) {
+			next.Restarts = append(next.Restarts, t)
+		}
+	}
+	next.Vanished = append(next.Vanished, vanished[replaced:]...)
+	sort.Slice(next.Restarts, func(i, j int) bool { return next.Restarts[i].Before(next.Restarts[j]) })
+	return next
+}
+
+// withinWindow returns the times after cutoff
+func withinWindow(times []time.Time, cutoff time.Time) []time.Time {
+	kept := make([]time.Time, 0, len(times))
+	for _, t := range times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// containsStart reports whether an instance is in a list, allowing for
+// start time jitter
+func containsStart(starts []processStart, s processStart) bool {
+	for _, start := range starts {
+		diff := start.StartTime.Sub(s.StartTime)
+		if start.PID == s.PID && diff < startTimeTolerance && diff > -startTimeTolerance {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/common"
+)
+
+// DefaultProcRoot is where procfs is normally mounted
+const DefaultProcRoot = "/proc"
+
+// procPath joins elem onto a procfs root; an empty root means DefaultProcRoot
+func procPath(root string, elem ...string) string {
+	if root == "" {
+		root = DefaultProcRoot
+	}
+	return filepath.Join(append([]string{root}, elem...)...)
+}
+
+// withProcRoot points gopsutil calls made with the returned context at a
+// procfs root; an empty root leaves ctx unchanged
+func withProcRoot(ctx context.Context, root string) context.Context {
+	if root == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, common.EnvKey, common.EnvMap{common.HostProcEnvKey: root})
+}
+
+// readProcUint reads a procfs file holding a single unsigned integer
+func readProcUint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return value, nil
+}
+
+// readProcUptime reads the system uptime from <proc root>/uptime
+func readProcUptime(root string) (time.Duration, error) {
+	path := procPath(root, "uptime")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("parse %s: empty file", path)
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// Check is a single pluggable health check
+type Check interface {
+	// Name returns the unique key the check is registered and reported under
+	Name() string
+	// Collect gathers raw data for the check and keeps it on the check.
+	// Checks run concurrently, so Collect must not touch shared state and
+	// should return promptly once ctx is done.
+	Collect(ctx context.Context) error
+	// Evaluate records the collected data on metrics and turns it into a
+	// result using thresholds. It is only called after Collect succeeded.
+	Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult
+}
+
+// Registry holds the checks run by a HealthChecker, in registration order
+type Registry struct {
+	checks []Check
+	names  map[string]bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		checks: make([]Check, 0),
+		names:  make(map[string]bool),
+	}
+}
+
+// builtinChecks lists the built-in checks in their default run order
+var builtinChecks = []struct {
+	name string
+	new  func(opts *Options) Check
+}{
+	{"cpu", func(opts *Options) Check { return &CPUCheck{Options: opts.CPU} }},
+	{"load", func(opts *Options) Check { return &LoadCheck{} }},
+	{"memory", func(opts *Options) Check { return &MemoryCheck{Options: opts.Memory} }},
+	{"pressure", func(opts *Options) Check { return &PressureCheck{ProcRoot: opts.ProcRoot} }},
+	{"disks", func(opts *Options) Check { return &DiskCheck{Options: opts.Disk} }},
+	{"diskio", func(opts *Options) Check { return &DiskIOCheck{SysRoot: opts.SysRoot, Options: opts.DiskIO} }},
+	{"mounts", func(opts *Options) Check { return &MountCheck{ProcRoot: opts.ProcRoot, Options: opts.Disk} }},
+	{"network", func(opts *Options) Check { return &NetworkCheck{SysRoot: opts.SysRoot, Options: opts.Network} }},
+	{"ports", func(opts *Options) Check { return &PortCheck{Options: opts.Ports} }},
+	{"kernel", func(opts *Options) Check { return &KernelTablesCheck{ProcRoot: opts.ProcRoot} }},
+	{"tcp", func(opts *Options) Check { return &TCPCheck{ProcRoot: opts.ProcRoot, Options: opts.TCP} }},
+	{"temperature", func(opts *Options) Check { return &TemperatureCheck{SysRoot: opts.SysRoot} }},
+	{"processes", func(opts *Options) Check { return &ProcessCheck{Specs: opts.Processes, Options: opts.Process} }},
+}
+
+// BuiltinCheckNames returns the names of the built-in checks
+func BuiltinCheckNames() []string {
+	names := make([]string, 0, len(builtinChecks))
+	for _, b := range builtinChecks {
+		names = append(names, b.name)
+	}
+	return names
+}
+
+// NewDefaultRegistry returns a registry with all built-in checks
+// using the default options
+func NewDefaultRegistry() *Registry {
+	r, err := NewRegistryFor(BuiltinCheckNames(), NewDefaultOptions())
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// NewRegistryFor returns a registry with only the named built-in checks,
+// in their default run order, configured with opts
+func NewRegistryFor(names []string, opts *Options) (*Registry, error) {
+	if opts == nil {
+		opts = NewDefaultOptions()
+	}
+
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		enabled[name] = true
+	}
+
+	r := NewRegistry()
+	for _, b := range builtinChecks {
+		if enabled[b.name] {
+			r.MustRegister(b.new(opts))
+			delete(enabled, b.name)
+		}
+	}
+	for name := range enabled {
+		return nil, fmt.Errorf("unknown check: %s (available: %s)", name, strings.Join(BuiltinCheckNames(), ", "))
+	}
+	return r, nil
+}
+
+// Register adds a check to the registry
+func (r *Registry) Register(check Check) error {
+	name := check.Name()
+	if name == "" {
+		return fmt.Errorf("check name must not be empty")
+	}
+	if r.names[name] {
+		return fmt.Errorf("check already registered: %s", name)
+	}
+	r.names[name] = true
+	r.checks = append(r.checks, check)
+	return nil
+}
+
+// MustRegister is like Register but panics on error
+func (r *Registry) MustRegister(check Check) {
+	if err := r.Register(check); err != nil {
+		panic(err)
+	}
+}
+
+// Has reports whether a check is registered under name
+func (r *Registry) Has(name string) bool {
+	return r.names[name]
+}
+
+// Checks returns the registered checks in registration order
+func (r *Registry) Checks() []Check {
+	return r.checks
+}
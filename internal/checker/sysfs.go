@@ -0,0 +1,37 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultSysRoot is where sysfs is normally mounted
+const DefaultSysRoot = "/sys"
+
+// sysPath joins elem onto a sysfs root; an empty root means DefaultSysRoot
+func sysPath(root string, elem ...string) string {
+	if root == "" {
+		root = DefaultSysRoot
+	}
+	return filepath.Join(append([]string{root}, elem...)...)
+}
+
+// readSysString reads a one-line sysfs attribute without its newline
+func readSysString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readSysInt reads a sysfs attribute holding a single integer
+func readSysInt(path string) (int64, error) {
+	text, err := readSysString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(text, 10, 64)
+}
@@ -0,0 +1,330 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// DefaultTCPInterval is the TCP counter sampling window used when none is
+// configured
+const DefaultTCPInterval = time.Second
+
+// tcpStates maps the hex state column of /proc/net/tcp to state names
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+	"0C": "NEW_SYN_RECV",
+}
+
+// TCPCheck counts sockets by TCP state, measures ephemeral port usage
+// against ip_local_port_range and reports listen queue overflows, all
+// read from procfs
+type TCPCheck struct {
+	// ProcRoot is where procfs is mounted; empty means DefaultProcRoot
+	ProcRoot string
+	Options  TCPOptions
+	info     *models.TCPInfo
+}
+
+// TCPResult is the typed result of the tcp check
+type TCPResult struct {
+	States             map[string]int `json:"states"`
+	TimeWaitStatus     models.Status  `json:"time_wait_status"`
+	EphemeralUsed      int            `json:"ephemeral_used"`
+	EphemeralRange     [2]int         `json:"ephemeral_range"`
+	EphemeralPercent   float64        `json:"ephemeral_percent"`
+	EphemeralStatus    models.Status  `json:"ephemeral_status"`
+	ListenOverflowRate float64        `json:"listen_overflows_per_sec"`
+	ListenDropRate     float64        `json:"listen_drops_per_sec"`
+	ListenStatus       models.Status  `json:"listen_status"`
+	Status             models.Status  `json:"status"`
+}
+
+func (c *TCPCheck) Name() string {
+	return "tcp"
+}
+
+// Collect reads <proc root>/net/tcp{,6}, the ephemeral port range and
+// samples the TcpExt listen counters Options.Interval apart. With a zero
+// interval the rates are averages since boot.
+func (c *TCPCheck) Collect(ctx context.Context) error {
+	// - Read the TcpExt listen counters, twice Options.Interval apart
+	//   ELSE once, compared against zero over the uptime
+	before, after, window, err := sampleWindow(ctx, time.Duration(c.Options.Interval), func() (listenCounters, error) {
+		return readListenCounters(procPath(c.ProcRoot, "net", "netstat"))
+	})
+	if err != nil {
+		return err
+	}
+	if c.Options.Interval <= 0 {
+		window, err = readProcUptime(c.ProcRoot)
+		if err != nil {
+			return err
+		}
+	}
+
+	// - Read ip_local_port_range
+	low, high, err := readPortRange(procPath(c.ProcRoot, "sys", "net", "ipv4", "ip_local_port_range"))
+	if err != nil {
+		return err
+	}
+	info := &models.TCPInfo{
+		States:        make(map[string]int),
+		EphemeralLow:  low,
+		EphemeralHigh: high,
+	}
+
+	// - FOR EACH of net/tcp and net/tcp6: count sockets by state and
+	//   collect local ports in the ephemeral range (tcp6 may be missing)
+	ports := make(map[int]bool)
+	for _, name := range []string{"tcp", "tcp6"} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := readTCPSockets(procPath(c.ProcRoot, "net", name), info.States, func(port int) {
+			if port >= low && port <= high {
+				ports[port] = true
+			}
+		})
+		if errors.Is(err, fs.ErrNotExist) && name == "tcp6" {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	info.EphemeralUsed = len(ports)
+	info.ListenOverflowRate = counterRate(before.overflows, after.overflows, window)
+	info.ListenDropRate = counterRate(before.drops, after.drops, window)
+
+	c.info = info
+	// - Return nil
+	return nil
+}
+
+// Evaluate compares TIME_WAIT sockets, ephemeral port usage and listen
+// overflows against the TCP thresholds
+func (c *TCPCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.TCP = c.info
+	ti := c.info
+
+	result := models.NewCheckResult(c.Name())
+
+	// Socket states, judged by TIME_WAIT
+	timeWaitStatus := ti.GetTimeWaitStatus(thresholds)
+	result.Status = result.Status.Worse(timeWaitStatus)
+	result.AddRow(
+		"TCP States",
+		formatTCPStates(ti.States),
+		timeWaitStatus,
+		fmt.Sprintf("TIME_WAIT < %.0f", thresholds.TCPTimeWaitWarning),
+	)
+
+	// Ephemeral ports
+	ephemeralStatus := ti.GetEphemeralStatus(thresholds)
+	result.Status = result.Status.Worse(ephemeralStatus)
+	result.AddRow(
+		"TCP Ephemeral Ports",
+		fmt.Sprintf("%d / %d (%.1f%%, range %d-%d)", ti.EphemeralUsed, ti.GetEphemeralRange(), ti.GetEphemeralPercent(), ti.EphemeralLow, ti.EphemeralHigh),
+		ephemeralStatus,
+		fmt.Sprintf("< %.0f%%", thresholds.EphemeralPortWarning),
+	)
+
+	// Listen queue overflows
+	listenStatus := ti.GetListenOverflowStatus(thresholds)
+	result.Status = result.Status.Worse(listenStatus)
+	result.AddRow(
+		"TCP Listen Overflows",
+		fmt.Sprintf("%.2f/s overflows, %.2f/s drops", ti.ListenOverflowRate, ti.ListenDropRate),
+		listenStatus,
+		fmt.Sprintf("< %.0f/s", thresholds.ListenOverflowWarning),
+	)
+
+	result.Data = TCPResult{
+		States:             ti.States,
+		TimeWaitStatus:     timeWaitStatus,
+		EphemeralUsed:      ti.EphemeralUsed,
+		EphemeralRange:     [2]int{ti.EphemeralLow, ti.EphemeralHigh},
+		EphemeralPercent:   ti.GetEphemeralPercent(),
+		EphemeralStatus:    ephemeralStatus,
+		ListenOverflowRate: ti.ListenOverflowRate,
+		ListenDropRate:     ti.ListenDropRate,
+		ListenStatus:       listenStatus,
+		Status:             result.Status,
+	}
+	return result
+}
+
+// formatTCPStates lists the most common states first, e.g.
+// "ESTABLISHED 120, TIME_WAIT 35, LISTEN 8"
+func formatTCPStates(states map[string]int) string {
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "no sockets"
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if states[names[i]] != states[names[j]] {
+			return states[names[i]] > states[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %d", name, states[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// readTCPSockets counts the sockets in a /proc/net/tcp style file by
+// state and calls ephemeral with the local port of every non-listening
+// socket
+func readTCPSockets(path string, states map[string]int, ephemeral func(port int)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := parseTCPSockets(f, states, ephemeral); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// parseTCPSockets parses lines of the form
+//
+//	sl  local_address rem_address   st ...
+//	 0: 0100007F:BC8F 00000000:0000 0A ...
+func parseTCPSockets(r io.Reader, states map[string]int, ephemeral func(port int)) error {
+	scanner := bufio.NewScanner(r)
+	// - Skip the header line
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		state, ok := tcpStates[strings.ToUpper(fields[3])]
+		if !ok {
+			state = "UNKNOWN"
+		}
+		states[state]++
+		if state == "LISTEN" {
+			continue
+		}
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			return fmt.Errorf("malformed local address %q", fields[1])
+		}
+		port, err := strconv.ParseUint(hexPort, 16, 16)
+		if err != nil {
+			return fmt.Errorf("local address %q: %w", fields[1], err)
+		}
+		ephemeral(int(port))
+	}
+	return scanner.Err()
+}
+
+// readPortRange reads ip_local_port_range ("32768\t60999")
+func readPortRange(path string) (low, high int, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("parse %s: expected two ports, got %q", path, strings.TrimSpace(string(data)))
+	}
+	if low, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if high, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return low, high, nil
+}
+
+// listenCounters are the TcpExt listen queue counters since boot
+type listenCounters struct {
+	overflows uint64
+	drops     uint64
+}
+
+// readListenCounters reads ListenOverflows and ListenDrops from a
+// /proc/net/netstat style file
+func readListenCounters(path string) (listenCounters, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return listenCounters{}, err
+	}
+	defer f.Close()
+
+	values, err := parseNetstat(f, "TcpExt")
+	if err != nil {
+		return listenCounters{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return listenCounters{
+		overflows: values["ListenOverflows"],
+		drops:     values["ListenDrops"],
+	}, nil
+}
+
+// parseNetstat returns the counters of one section of a netstat file,
+// where each section is a line of names followed by a line of values:
+//
+//	TcpExt: SyncookiesSent ListenOverflows ListenDrops
+//	TcpExt: 0 5 7
+func parseNetstat(r io.Reader, section string) (map[string]uint64, error) {
+	prefix := section + ":"
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		names := strings.Fields(scanner.Text())
+		if len(names) == 0 || names[0] != prefix {
+			continue
+		}
+		if !scanner.Scan() {
+			break
+		}
+		values := strings.Fields(scanner.Text())
+		if len(values) != len(names) || values[0] != prefix {
+			return nil, fmt.Errorf("%s: names and values do not match", section)
+		}
+		counters := make(map[string]uint64, len(names)-1)
+		for i := 1; i < len(names); i++ {
+			v, err := strconv.ParseUint(values[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", section, names[i], err)
+			}
+			counters[names[i]] = v
+		}
+		return counters, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("missing %s section", section)
+}
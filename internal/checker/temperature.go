@@ -0,0 +1,230 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// TemperatureCheck reports hardware temperature sensors from hwmon and
+// thermal zones in sysfs
+type TemperatureCheck struct {
+	// SysRoot is where sysfs is mounted; empty means DefaultSysRoot
+	SysRoot string
+	sensors []*models.TemperatureInfo
+}
+
+// TemperatureResult is the typed result of the temperature check for one sensor
+type TemperatureResult struct {
+	Source   string        `json:"source"`
+	Chip     string        `json:"chip"`
+	Label    string        `json:"label"`
+	Celsius  float64       `json:"celsius"`
+	Warning  float64       `json:"warning"`
+	Critical float64       `json:"critical"`
+	Status   models.Status `json:"status"`
+}
+
+func (c *TemperatureCheck) Name() string {
+	return "temperature"
+}
+
+// Collect reads every tempN_input under <sys root>/class/hwmon and every
+// zone under <sys root>/class/thermal. Sensors that cannot be read are
+// skipped; hosts without either class report no sensors.
+func (c *TemperatureCheck) Collect(ctx context.Context) error {
+	c.sensors = make([]*models.TemperatureInfo, 0)
+
+	hwmon, err := readHwmon(sysPath(c.SysRoot, "class", "hwmon"))
+	if err != nil {
+		return err
+	}
+	c.sensors = append(c.sensors, hwmon...)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	thermal, err := readThermalZones(sysPath(c.SysRoot, "class", "thermal"))
+	if err != nil {
+		return err
+	}
+	c.sensors = append(c.sensors, thermal...)
+	return nil
+}
+
+// Evaluate compares every sensor against its limits
+func (c *TemperatureCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	metrics.Temperatures = c.sensors
+
+	result := models.NewCheckResult(c.Name())
+	if len(metrics.Temperatures) == 0 {
+		result.AddRow("Temperature", "no sensors found", models.StatusOK, "")
+	}
+	sensors := make([]TemperatureResult, 0, len(metrics.Temperatures))
+	for _, s := range metrics.Temperatures {
+		warning, critical := s.Limits(thresholds)
+		status := s.GetStatus(thresholds)
+		result.Status = result.Status.Worse(status)
+		result.AddRow(
+			fmt.Sprintf("Temp %s %s", s.Chip, s.Label),
+			fmt.Sprintf("%.1f°C", s.Celsius),
+			status,
+			fmt.Sprintf("< %.0f°C", warning),
+		)
+		sensors = append(sensors, TemperatureResult{
+			Source:   s.Source,
+			Chip:     s.Chip,
+			Label:    s.Label,
+			Celsius:  s.Celsius,
+			Warning:  warning,
+			Critical: critical,
+			Status:   status,
+		})
+	}
+	result.Data = sensors
+	return result
+}
+
+// readHwmon reads the temperature inputs of every hwmon device in dir
+func readHwmon(dir string) ([]*models.TemperatureInfo, error) {
+	devices, err := sysDirNames(dir, "hwmon")
+	if err != nil {
+		return nil, err
+	}
+
+	sensors := make([]*models.TemperatureInfo, 0)
+	for _, device := range devices {
+		path := filepath.Join(dir, device)
+		chip, err := readSysString(filepath.Join(path, "name"))
+		if err != nil {
+			chip = device
+		}
+		inputs, err := filepath.Glob(filepath.Join(path, "temp*_input"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(inputs)
+		for _, input := range inputs {
+			// - "temp1_input" -> "temp1"; the label defaults to that prefix
+			prefix := strings.TrimSuffix(filepath.Base(input), "_input")
+			milli, err := readSysInt(input)
+			if err != nil {
+				continue
+			}
+			label, err := readSysString(filepath.Join(path, prefix+"_label"))
+			if err != nil || label == "" {
+				label = prefix
+			}
+			sensors = append(sensors, &models.TemperatureInfo{
+				Source:  "hwmon",
+				Chip:    chip,
+				Label:   label,
+				Celsius: milliToCelsius(milli),
+				Max:     readMilliCelsius(filepath.Join(path, prefix+"_max")),
+				Crit:    readMilliCelsius(filepath.Join(path, prefix+"_crit")),
+			})
+		}
+	}
+	return sensors, nil
+}
+
+// readThermalZones reads every thermal_zoneN in dir. The lowest "passive"
+// or "hot" trip point is used as the zone's max and the "critical" trip
+// point as its crit.
+func readThermalZones(dir string) ([]*models.TemperatureInfo, error) {
+	zones, err := sysDirNames(dir, "thermal_zone")
+	if err != nil {
+		return nil, err
+	}
+
+	sensors := make([]*models.TemperatureInfo, 0, len(zones))
+	for _, zone := range zones {
+		path := filepath.Join(dir, zone)
+		milli, err := readSysInt(filepath.Join(path, "temp"))
+		if err != nil {
+			continue
+		}
+		label, err := readSysString(filepath.Join(path, "type"))
+		if err != nil || label == "" {
+			label = zone
+		}
+		info := &models.TemperatureInfo{
+			Source:  "thermal",
+			Chip:    zone,
+			Label:   label,
+			Celsius: milliToCelsius(milli),
+		}
+
+		trips, err := filepath.Glob(filepath.Join(path, "trip_point_*_type"))
+		if err != nil {
+			return nil, err
+		}
+		for _, trip := range trips {
+			kind, err := readSysString(trip)
+			if err != nil {
+				continue
+			}
+			limit := readMilliCelsius(strings.TrimSuffix(trip, "_type") + "_temp")
+			if limit <= 0 {
+				continue
+			}
+			switch kind {
+			case "passive", "hot":
+				if info.Max == 0 || limit < info.Max {
+					info.Max = limit
+				}
+			case "critical":
+				info.Crit = limit
+			}
+		}
+		sensors = append(sensors, info)
+	}
+	return sensors, nil
+}
+
+// sysDirNames lists the entries of a sysfs class directory that start
+// with prefix, in numeric order; a missing directory has no entries
+func sysDirNames(dir, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), prefix) {
+			names = append(names, e.Name())
+		}
+	}
+	// - Sort hwmon2 before hwmon10
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names, nil
+}
+
+// readMilliCelsius reads a millidegree attribute; missing or unreadable
+// attributes yield 0, meaning "not provided"
+func readMilliCelsius(path string) float64 {
+	milli, err := readSysInt(path)
+	if err != nil || milli <= 0 {
+		return 0
+	}
+	return milliToCelsius(milli)
+}
+
+func milliToCelsius(milli int64) float64 {
+	return float64(milli) / 1000.0
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/andinianst93/system-health-checker/internal/checker"
+	"github.com/andinianst93/system-health-checker/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the file-based configuration of a health check run.
+// YAML, TOML and JSON files all use the keys given by the json tags.
+type Config struct {
+	Format        string                     `json:"format"`
+	Checks        []string                   `json:"checks"`
+	Timeout       models.Duration            `json:"timeout"`
+	CheckTimeouts map[string]models.Duration `json:"check_timeouts"`
+	Thresholds    *models.Thresholds         `json:"thresholds"`
+	// Options are inlined, so e.g. the disk options live under "disk"
+	checker.Options
+}
+
+// NewDefaultConfig returns the configuration used when no file is given
+func NewDefaultConfig() *Config {
+	return &Config{
+		Format:        "table",
+		CheckTimeouts: make(map[string]models.Duration),
+		Thresholds:    models.NewDefaultThresholds(),
+		Options:       *checker.NewDefaultOptions(),
+	}
+}
+
+// Load reads a configuration file on top of the defaults. The format is
+// chosen by extension: .yaml/.yml, .toml or .json. Unknown keys are
+// rejected so typos do not silently fall back to defaults.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// - Decode YAML and TOML into a generic map first, then re-encode it
+	//   as JSON so every format shares the json tags on Config
+	var raw map[string]interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q: must be .yaml, .yml, .toml or .json", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if raw == nil {
+		raw = make(map[string]interface{})
+	}
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	cfg := NewDefaultConfig()
+	dec := json.NewDecoder(bytes.NewReader(normalized))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if cfg.Thresholds == nil {
+		cfg.Thresholds = models.NewDefaultThresholds()
+	}
+	if cfg.CheckTimeouts == nil {
+		cfg.CheckTimeouts = make(map[string]models.Duration)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes that can be written with a unit, e.g.
+// "2GiB", "500MB" or a plain number of bytes. IEC units (KiB, MiB, GiB,
+// TiB) and the short forms K, M, G, T are powers of 1024; SI units
+// (KB, MB, GB, TB) are powers of 1000.
+type ByteSize uint64
+
+var byteUnits = map[string]uint64{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KIB": 1 << 10,
+	"KB":  1000,
+	"M":   1 << 20,
+	"MIB": 1 << 20,
+	"MB":  1000 * 1000,
+	"G":   1 << 30,
+	"GIB": 1 << 30,
+	"GB":  1000 * 1000 * 1000,
+	"T":   1 << 40,
+	"TIB": 1 << 40,
+	"TB":  1000 * 1000 * 1000 * 1000,
+}
+
+// ParseByteSize parses a size such as "2GiB" or "1048576"
+func ParseByteSize(text string) (ByteSize, error) {
+	text = strings.TrimSpace(text)
+	i := strings.IndexFunc(text, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(text)
+	}
+	number, unit := text[:i], strings.ToUpper(strings.TrimSpace(text[i:]))
+
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", text, text[i:])
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", text)
+	}
+	return ByteSize(value * float64(multiplier)), nil
+}
+
+// String formats the size with the largest IEC unit that fits
+func (b ByteSize) String() string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	value := float64(b)
+	i := 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%dB", uint64(b))
+	}
+	return fmt.Sprintf("%.1f%s", value, units[i])
+}
+
+// Set implements flag.Value
+func (b *ByteSize) Set(text string) error {
+	size, err := ParseByteSize(text)
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// UnmarshalJSON accepts either a number of bytes or a string with a unit
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return b.Set(text)
+	}
+	var n uint64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid size %s", data)
+	}
+	*b = ByteSize(n)
+	return nil
+}
@@ -0,0 +1,23 @@
+package models
+
+// CPUTimes is the share of CPU time spent in each state over the sample
+// window, in percent of all logical CPUs
+type CPUTimes struct {
+	User   float64
+	System float64
+	Iowait float64
+	Steal  float64
+	Idle   float64
+}
+
+// GetHottestCore returns the index and usage of the busiest core, or
+// -1 when no per-core data was collected
+func (sm *SystemMetrics) GetHottestCore() (int, float64) {
+	index, percent := -1, 0.0
+	for i, p := range sm.CPUCores {
+		if index < 0 || p > percent {
+			index, percent = i, p
+		}
+	}
+	return index, percent
+}
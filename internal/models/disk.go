@@ -1,18 +1,35 @@
 package models
 
+import "fmt"
+
 type DiskInfo struct {
 	MountPoint string
+	Device     string
+	FSType     string
+	Options    []string
 	UsedBytes  uint64
 	TotalBytes uint64
+	// FreeBytes is the space available to unprivileged users; it excludes
+	// blocks reserved for root, so it can be less than TotalBytes-UsedBytes
+	FreeBytes uint64
+	// Inode counts; InodesTotal is 0 on filesystems without fixed inodes
+	InodesUsed  uint64
+	InodesTotal uint64
 }
 
 func NewDiskInfo(mountPoint string, used, total uint64) *DiskInfo {
 	// - Create new DiskInfo with parameters
+	// - Default FreeBytes to total - used (no reserved blocks)
 	// - Return pointer to struct
+	var free uint64
+	if used < total {
+		free = total - used
+	}
 	return &DiskInfo{
 		MountPoint: mountPoint,
 		UsedBytes:  used,
 		TotalBytes: total,
+		FreeBytes:  free,
 	}
 }
 
@@ -36,18 +53,75 @@ func (di *DiskInfo) GetFreePercent() float64 {
 	return freePercentage
 }
 
+// GetFreeBytes returns the space in bytes available to unprivileged users
+func (di *DiskInfo) GetFreeBytes() uint64 {
+	return di.FreeBytes
+}
+
+// ReadOnly reports whether the filesystem is mounted read-only
+func (di *DiskInfo) ReadOnly() bool {
+	return hasReadOnlyOption(di.Options)
+}
+
+// GetInodePercent calculates used inode percentage
+func (di *DiskInfo) GetInodePercent() float64 {
+	if di.InodesTotal == 0 {
+		return 0.0
+	}
+	return float64(di.InodesUsed) / float64(di.InodesTotal) * 100
+}
+
+// GetInodeStatus determines inode health status. Filesystems that do not
+// report inodes (e.g. btrfs, vfat) are always OK.
+func (di *DiskInfo) GetInodeStatus(thresholds *Thresholds) Status {
+	if di.InodesTotal == 0 {
+		return StatusOK
+	}
+	return EvaluateHigher(di.GetInodePercent(), thresholds.InodeWarning, thresholds.InodeCritical)
+}
+
 // GetStatus determines disk health status
-func (di *DiskInfo) GetStatus(thresholds *Thresholds) string {
-	// - Get free percentage
+func (di *DiskInfo) GetStatus(thresholds *Thresholds) Status {
+	status, _ := di.Evaluate(thresholds)
+	return status
+}
+
+// Evaluate determines disk health status and describes the rule that
+// produced it, e.g. "< 10% free". The percent and absolute free-space
+// rules are both applied and the worse result wins. The rule is empty
+// when the disk is OK.
+func (di *DiskInfo) Evaluate(thresholds *Thresholds) (Status, string) {
+	// - Get the limits that apply to this mount point
+	limits := thresholds.ForDisk(di.MountPoint)
+
+	// - Evaluate free percent: below critical => CRITICAL, below warning => WARNING
+	status, rule := StatusOK, ""
 	freePercentage := di.GetFreePercent()
-	// - IF free < thresholds.DiskCritical THEN return "CRITICAL"
-	// - ELSE IF free < thresholds.DiskWarning THEN return "WARNING"
-	// - ELSE return "OK"
-	if freePercentage < thresholds.DiskCritical {
-		return "CRITICAL"
-	} else if freePercentage < thresholds.DiskWarning {
-		return "WARNING"
-	} else {
-		return "OK"
+	if s := EvaluateLower(freePercentage, limits.Warning, limits.Critical); s > status {
+		status = s
+		rule = fmt.Sprintf("< %.0f%% free", limitFor(s, limits.Warning, limits.Critical))
+	}
+
+	// - Evaluate free bytes the same way, skipping disabled (zero) limits
+	freeBytes := di.GetFreeBytes()
+	bytesStatus := StatusOK
+	if limits.CriticalBytes > 0 && freeBytes < uint64(limits.CriticalBytes) {
+		bytesStatus = StatusCritical
+	} else if limits.WarningBytes > 0 && freeBytes < uint64(limits.WarningBytes) {
+		bytesStatus = StatusWarning
+	}
+	if bytesStatus > status {
+		status = bytesStatus
+		rule = fmt.Sprintf("< %s free", ByteSize(limitFor(bytesStatus, float64(limits.WarningBytes), float64(limits.CriticalBytes))))
+	}
+
+	return status, rule
+}
+
+// limitFor returns the limit that belongs to a WARNING or CRITICAL status
+func limitFor(status Status, warning, critical float64) float64 {
+	if status == StatusCritical {
+		return critical
 	}
+	return warning
 }
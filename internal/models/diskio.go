@@ -0,0 +1,31 @@
+package models
+
+// DiskIOInfo holds I/O rates for one block device, measured over a
+// sampling window
+type DiskIOInfo struct {
+	Device           string
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+	ReadIOPS         float64
+	WriteIOPS        float64
+	// AwaitMs is the average time in milliseconds an I/O request took,
+	// including time spent queued
+	AwaitMs float64
+	// UtilPercent is the share of time the device was busy
+	UtilPercent float64
+}
+
+// GetUtilStatus evaluates device utilization against the thresholds
+func (di *DiskIOInfo) GetUtilStatus(thresholds *Thresholds) Status {
+	return EvaluateHigher(di.UtilPercent, thresholds.DiskUtilWarning, thresholds.DiskUtilCritical)
+}
+
+// GetAwaitStatus evaluates average I/O latency against the thresholds
+func (di *DiskIOInfo) GetAwaitStatus(thresholds *Thresholds) Status {
+	return EvaluateHigher(di.AwaitMs, thresholds.DiskAwaitWarning, thresholds.DiskAwaitCritical)
+}
+
+// GetStatus returns the worse of the utilization and latency statuses
+func (di *DiskIOInfo) GetStatus(thresholds *Thresholds) Status {
+	return di.GetUtilStatus(thresholds).Worse(di.GetAwaitStatus(thresholds))
+}
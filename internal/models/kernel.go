@@ -0,0 +1,58 @@
+package models
+
+// KernelTablesInfo holds the fill level of system-wide kernel tables
+type KernelTablesInfo struct {
+	// FileHandles is allocated minus unused handles from fs/file-nr
+	FileHandles    uint64
+	FileHandlesMax uint64
+	// Tasks is the number of processes and threads, which each use a PID
+	Tasks      uint64
+	PIDMax     uint64
+	ThreadsMax uint64
+	// Conntrack is only set when the nf_conntrack module is loaded
+	HasConntrack   bool
+	ConntrackCount uint64
+	ConntrackMax   uint64
+}
+
+// GetFileHandlePercent calculates the share of file handles in use
+func (ki *KernelTablesInfo) GetFileHandlePercent() float64 {
+	return fillPercent(ki.FileHandles, ki.FileHandlesMax)
+}
+
+// GetPIDPercent calculates task usage against the lower of pid_max and
+// threads-max, whichever runs out first
+func (ki *KernelTablesInfo) GetPIDPercent() float64 {
+	return max(fillPercent(ki.Tasks, ki.PIDMax), fillPercent(ki.Tasks, ki.ThreadsMax))
+}
+
+// GetConntrackPercent calculates the conntrack table fill level
+func (ki *KernelTablesInfo) GetConntrackPercent() float64 {
+	return fillPercent(ki.ConntrackCount, ki.ConntrackMax)
+}
+
+// GetFileHandleStatus evaluates file handle usage against the thresholds
+func (ki *KernelTablesInfo) GetFileHandleStatus(thresholds *Thresholds) Status {
+	return EvaluateHigher(ki.GetFileHandlePercent(), thresholds.FileHandleWarning, thresholds.FileHandleCritical)
+}
+
+// GetPIDStatus evaluates task usage against the thresholds
+func (ki *KernelTablesInfo) GetPIDStatus(thresholds *Thresholds) Status {
+	return EvaluateHigher(ki.GetPIDPercent(), thresholds.PIDWarning, thresholds.PIDCritical)
+}
+
+// GetConntrackStatus evaluates the conntrack fill level; OK without conntrack
+func (ki *KernelTablesInfo) GetConntrackStatus(thresholds *Thresholds) Status {
+	if !ki.HasConntrack {
+		return StatusOK
+	}
+	return EvaluateHigher(ki.GetConntrackPercent(), thresholds.ConntrackWarning, thresholds.ConntrackCritical)
+}
+
+// fillPercent calculates used as a percentage of limit; 0 without a limit
+func fillPercent(used, limit uint64) float64 {
+	if limit == 0 {
+		return 0.0
+	}
+	return float64(used) / float64(limit) * 100
+}
@@ -0,0 +1,20 @@
+package models
+
+// LoadInfo holds load averages and the run queue
+type LoadInfo struct {
+	Load1  float64
+	Load5  float64
+	Load15 float64
+	// CPUs is the number of logical CPUs used to normalize the averages
+	CPUs         int
+	ProcsRunning int
+	ProcsBlocked int
+}
+
+// PerCPU normalizes a load average by the number of logical CPUs
+func (li *LoadInfo) PerCPU(load float64) float64 {
+	if li.CPUs <= 0 {
+		return load
+	}
+	return load / float64(li.CPUs)
+}
@@ -6,11 +6,33 @@ import (
 
 type SystemMetrics struct {
 	CPUPercent  float64
+	CPUCores    []float64
+	CPUTimes    CPUTimes
+	Load        *LoadInfo
 	MemoryUsed  uint64
 	MemoryTotal uint64
-	Disks       []*DiskInfo
-	Processes   []*ProcessInfo
-	CheckTime   time.Time
+	// MemoryAvailable is memory usable without swapping, including
+	// reclaimable page cache
+	MemoryAvailable uint64
+	MemoryBuffers   uint64
+	MemoryCached    uint64
+	SwapUsed        uint64
+	SwapTotal       uint64
+	// SwapInRate and SwapOutRate are in bytes per second
+	SwapInRate   float64
+	SwapOutRate  float64
+	Pressure     []*PressureInfo
+	Disks        []*DiskInfo
+	DiskIO       []*DiskIOInfo
+	Network      []*NetInterfaceInfo
+	TCP          *TCPInfo
+	Ports        []*PortAssertion
+	Temperatures []*TemperatureInfo
+	KernelTables *KernelTablesInfo
+	Mounts       []*MountInfo
+	Processes    []*ProcessInfo
+	Results      []*CheckResult
+	CheckTime    time.Time
 }
 
 func NewSystemMetrics() *SystemMetrics {
@@ -20,23 +42,46 @@ func NewSystemMetrics() *SystemMetrics {
 	// - Set CheckTime to current time
 	// - Return pointer to struct
 	return &SystemMetrics{
-		CPUPercent:  0.0,
-		MemoryUsed:  0,
-		MemoryTotal: 0,
-		Disks:       make([]*DiskInfo, 0),
-		Processes:   make([]*ProcessInfo, 0),
-		CheckTime:   time.Now(),
+		CPUPercent:   0.0,
+		CPUCores:     make([]float64, 0),
+		MemoryUsed:   0,
+		MemoryTotal:  0,
+		Pressure:     make([]*PressureInfo, 0),
+		Disks:        make([]*DiskInfo, 0),
+		DiskIO:       make([]*DiskIOInfo, 0),
+		Network:      make([]*NetInterfaceInfo, 0),
+		Ports:        make([]*PortAssertion, 0),
+		Temperatures: make([]*TemperatureInfo, 0),
+		Mounts:       make([]*MountInfo, 0),
+		Processes:    make([]*ProcessInfo, 0),
+		Results:      make([]*CheckResult, 0),
+		CheckTime:    time.Now(),
 	}
 }
 
-// GetMemoryPercent calculates memory usage percentage
+// GetMemoryPercent calculates the percentage of memory in use, i.e. not
+// available. Reclaimable page cache counts as available.
 func (sm *SystemMetrics) GetMemoryPercent() float64 {
 	// - IF MemoryTotal equals 0 THEN return 0.0
 	if sm.MemoryTotal == 0 {
 		return 0.0
 	}
-	// - Calculate: (MemoryUsed / MemoryTotal) * 100
-	memoryPercentage := float64(sm.MemoryUsed) / float64(sm.MemoryTotal) * 100
+	// - IF MemoryAvailable exceeds MemoryTotal THEN nothing is in use
+	if sm.MemoryAvailable >= sm.MemoryTotal {
+		return 0.0
+	}
+	// - Calculate: ((MemoryTotal - MemoryAvailable) / MemoryTotal) * 100
+	memoryPercentage := float64(sm.MemoryTotal-sm.MemoryAvailable) / float64(sm.MemoryTotal) * 100
 
 	return memoryPercentage
 }
+
+// GetSwapPercent calculates swap usage percentage
+func (sm *SystemMetrics) GetSwapPercent() float64 {
+	// - IF SwapTotal equals 0 THEN return 0.0 (no swap configured)
+	if sm.SwapTotal == 0 {
+		return 0.0
+	}
+	// - Calculate: (SwapUsed / SwapTotal) * 100
+	return float64(sm.SwapUsed) / float64(sm.SwapTotal) * 100
+}
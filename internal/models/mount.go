@@ -0,0 +1,53 @@
+package models
+
+// MountInfo describes a mount point as seen in the mount table
+type MountInfo struct {
+	MountPoint string
+	Device     string
+	FSType     string
+	Options    []string
+	Present    bool
+	Required   bool
+}
+
+func NewMountInfo(mountPoint string) *MountInfo {
+	return &MountInfo{
+		MountPoint: mountPoint,
+		Options:    make([]string, 0),
+	}
+}
+
+// SetPartition records the mount table entry for this mount point
+func (mi *MountInfo) SetPartition(device, fsType string, options []string) {
+	mi.Present = true
+	mi.Device = device
+	mi.FSType = fsType
+	mi.Options = options
+}
+
+// ReadOnly reports whether the mount options contain "ro"
+func (mi *MountInfo) ReadOnly() bool {
+	return hasReadOnlyOption(mi.Options)
+}
+
+// GetStatus returns CRITICAL when a required mount is missing or when a
+// mount expected to be writable is read-only
+func (mi *MountInfo) GetStatus(expectWritable bool) Status {
+	if mi.Required && !mi.Present {
+		return StatusCritical
+	}
+	if expectWritable && mi.Present && mi.ReadOnly() {
+		return StatusCritical
+	}
+	return StatusOK
+}
+
+// hasReadOnlyOption reports whether mount options contain "ro"
+func hasReadOnlyOption(options []string) bool {
+	for _, opt := range options {
+		if opt == "ro" {
+			return true
+		}
+	}
+	return false
+}
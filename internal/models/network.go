@@ -0,0 +1,34 @@
+package models
+
+// NetInterfaceInfo holds link state and traffic rates for one network
+// interface, measured over a sampling window
+type NetInterfaceInfo struct {
+	Name string
+	// Present is false for a required interface that does not exist
+	Present  bool
+	Up       bool
+	Required bool
+	// Rates are per second
+	RxBytesPerSec   float64
+	TxBytesPerSec   float64
+	RxPacketsPerSec float64
+	TxPacketsPerSec float64
+	ErrorsPerSec    float64
+	DropsPerSec     float64
+}
+
+// GetLinkStatus returns CRITICAL when a required interface is missing or
+// down; other interfaces are only reported
+func (ni *NetInterfaceInfo) GetLinkStatus() Status {
+	if ni.Required && (!ni.Present || !ni.Up) {
+		return StatusCritical
+	}
+	return StatusOK
+}
+
+// GetStatus returns the worst of the link, error rate and drop rate statuses
+func (ni *NetInterfaceInfo) GetStatus(thresholds *Thresholds) Status {
+	errStatus := EvaluateHigher(ni.ErrorsPerSec, thresholds.NetErrorWarning, thresholds.NetErrorCritical)
+	dropStatus := EvaluateHigher(ni.DropsPerSec, thresholds.NetDropWarning, thresholds.NetDropCritical)
+	return ni.GetLinkStatus().Worse(errStatus).Worse(dropStatus)
+}
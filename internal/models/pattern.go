@@ -0,0 +1,17 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// MatchPattern reports whether value matches a glob pattern as used in
+// mount, device and filesystem type rules. Besides filepath.Match syntax,
+// a trailing "/**" matches the directory itself and everything below it.
+func MatchPattern(pattern, value string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return value == prefix || strings.HasPrefix(value, prefix+"/")
+	}
+	ok, _ := filepath.Match(pattern, value)
+	return ok
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ListenAddress is a TCP address a port assertion refers to. An empty
+// Host matches any local address.
+type ListenAddress struct {
+	Host string
+	Port uint32
+}
+
+// ParseListenAddress parses "port", "host:port" or "[ipv6]:port"
+func ParseListenAddress(spec string) (ListenAddress, error) {
+	spec = strings.TrimSpace(spec)
+	host, portText := "", spec
+	if strings.Contains(spec, ":") {
+		var err error
+		host, portText, err = net.SplitHostPort(spec)
+		if err != nil {
+			return ListenAddress{}, fmt.Errorf("invalid listen address %q: %w", spec, err)
+		}
+		if host != "" && net.ParseIP(host) == nil {
+			return ListenAddress{}, fmt.Errorf("invalid listen address %q: host must be an IP address", spec)
+		}
+	}
+	port, err := strconv.ParseUint(portText, 10, 16)
+	if err != nil || port == 0 {
+		return ListenAddress{}, fmt.Errorf("invalid listen address %q: port must be between 1 and 65535", spec)
+	}
+	return ListenAddress{Host: host, Port: uint32(port)}, nil
+}
+
+func (la ListenAddress) String() string {
+	if la.Host == "" {
+		return fmt.Sprintf("*:%d", la.Port)
+	}
+	return net.JoinHostPort(la.Host, strconv.FormatUint(uint64(la.Port), 10))
+}
+
+// Matches reports whether a listener bound to host:port matches this
+// address exactly; an empty Host matches any local address
+func (la ListenAddress) Matches(host string, port uint32) bool {
+	if la.Port != port {
+		return false
+	}
+	return la.Host == "" || sameIP(la.Host, host)
+}
+
+// ServedBy reports whether a listener bound to host:port accepts
+// connections on this address, i.e. it matches or listens on the
+// wildcard address of the same family
+func (la ListenAddress) ServedBy(host string, port uint32) bool {
+	if la.Matches(host, port) {
+		return true
+	}
+	if la.Port != port {
+		return false
+	}
+	ip := net.ParseIP(host)
+	want := net.ParseIP(la.Host)
+	if ip == nil || want == nil || !ip.IsUnspecified() {
+		return false
+	}
+	// - "::" also accepts IPv4 connections on dual-stack hosts
+	return ip.To4() == nil || want.To4() != nil
+}
+
+// sameIP compares two IP address strings, ignoring formatting differences
+func sameIP(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	return ipA.Equal(ipB)
+}
+
+// PortListener is a listening socket and, where permitted, its owner
+type PortListener struct {
+	Host string
+	Port uint32
+	// PID is 0 when the owning process could not be determined
+	PID     int32
+	Process string
+}
+
+// PortAssertion is an expectation about a listen address and the
+// listeners found for it
+type PortAssertion struct {
+	Address ListenAddress
+	// Expect is true for "must be listening" and false for "must not be"
+	Expect    bool
+	Listeners []PortListener
+}
+
+// Listening reports whether any matching listener was found
+func (pa *PortAssertion) Listening() bool {
+	return len(pa.Listeners) > 0
+}
+
+// GetStatus returns CRITICAL when the expectation is not met
+func (pa *PortAssertion) GetStatus() Status {
+	if pa.Listening() != pa.Expect {
+		return StatusCritical
+	}
+	return StatusOK
+}
@@ -0,0 +1,34 @@
+package models
+
+// PressureStat is one line of a Linux PSI file. The averages are the
+// percentage of wall time that tasks were stalled over the last 10, 60
+// and 300 seconds; Total is the cumulative stall time in microseconds.
+type PressureStat struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
+}
+
+// PressureInfo holds pressure stall information for one resource
+// (cpu, memory or io)
+type PressureInfo struct {
+	Resource string
+	// Some is the share of time at least one task was stalled
+	Some PressureStat
+	// Full is the share of time all non-idle tasks were stalled at once;
+	// nil when the kernel does not report it for this resource
+	Full *PressureStat
+}
+
+// GetStatus evaluates the "some" and "full" 10 second averages against
+// the thresholds for this resource; the worse result wins
+func (pi *PressureInfo) GetStatus(thresholds *Thresholds) Status {
+	warning, critical := thresholds.ForPressure(pi.Resource)
+	status := EvaluateHigher(pi.Some.Avg10, warning, critical)
+	if pi.Full != nil {
+		warning, critical = thresholds.ForPressureFull(pi.Resource)
+		status = status.Worse(EvaluateHigher(pi.Full.Avg10, warning, critical))
+	}
+	return status
+}
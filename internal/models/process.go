@@ -1,11 +1,35 @@
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type ProcessInfo struct {
-	PID           int32
-	Name          string
+	PID       int32
+	Name      string
+	Cmdline   string
+	Username  string
+	StartTime time.Time
+	// Uptime is how long the process had been running when collected
+	Uptime time.Duration
+	// CPUPercent is percent of one CPU over the sampling window, or over
+	// the process lifetime when there is none
 	CPUPercent    float64
 	MemoryPercent float64
-	Status        string
+	RSSBytes      uint64
+	NumFDs        int32
+	NumThreads    int32
+	// ReadBytesPerSec and WriteBytesPerSec are storage I/O rates
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+	Status           string
 }
 
 func NewProcessInfo(pid int32, name string) *ProcessInfo {
@@ -21,3 +45,352 @@ func NewProcessInfo(pid int32, name string) *ProcessInfo {
 		Status:        "unknown",
 	}
 }
+
+// ProcessSpec describes a process that should be running and how many
+// instances of it are expected. A process matches when it satisfies
+// every matcher that is set. The json tags define the keys used in
+// configuration files.
+type ProcessSpec struct {
+	// Name is the exact executable name, e.g. "nginx"
+	Name string `json:"name,omitempty"`
+	// Cmdline is a regular expression matched against the full command
+	// line, e.g. "kafka\.Kafka" for a JVM service
+	Cmdline string `json:"cmdline,omitempty"`
+	// User is the owning user name or numeric uid
+	User string `json:"user,omitempty"`
+	// Parent is the name or pid of the parent process
+	Parent string `json:"parent,omitempty"`
+	// Pidfile is a file holding the pid of the only process that may match
+	Pidfile string `json:"pidfile,omitempty"`
+	// Min is the minimum number of instances; fewer is CRITICAL
+	Min int `json:"min"`
+	// Max is the maximum number of instances; more is WARNING. Zero
+	// means no maximum.
+	Max int `json:"max"`
+	// ProcessLimits are checked against every matching instance
+	ProcessLimits
+}
+
+// ProcessLimits are per-process warning/critical thresholds; a zero
+// value disables that limit
+type ProcessLimits struct {
+	// CPUWarning and CPUCritical are percent of one CPU
+	CPUWarning      float64  `json:"cpu_warning,omitempty"`
+	CPUCritical     float64  `json:"cpu_critical,omitempty"`
+	RSSWarning      ByteSize `json:"rss_warning,omitempty"`
+	RSSCritical     ByteSize `json:"rss_critical,omitempty"`
+	FDsWarning      int      `json:"fds_warning,omitempty"`
+	FDsCritical     int      `json:"fds_critical,omitempty"`
+	ThreadsWarning  int      `json:"threads_warning,omitempty"`
+	ThreadsCritical int      `json:"threads_critical,omitempty"`
+	// IOWarning and IOCritical are read plus write bytes per second
+	IOWarning  ByteSize `json:"io_warning,omitempty"`
+	IOCritical ByteSize `json:"io_critical,omitempty"`
+}
+
+// ParseProcessSpec parses "name" (at least one instance), "name=N"
+// (exactly N), "name=MIN:MAX", "name=MIN:" or "name=:MAX". Further
+// matchers follow as ";"-separated key=value fields, e.g.
+// "java;cmdline=kafka\.Kafka;user=kafka"; the keys are name, cmdline,
+// user, parent, pidfile and count. Limits are given as
+// key=WARNING:CRITICAL with the keys cpu, rss, fds, threads and io,
+// e.g. "rss=1GiB:2GiB"; either side may be left empty.
+func ParseProcessSpec(text string) (ProcessSpec, error) {
+	spec := ProcessSpec{Min: 1}
+	for i, field := range strings.Split(text, ";") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		key, value, hasValue := strings.Cut(field, "=")
+		key = strings.TrimSpace(key)
+		var err error
+		switch {
+		case hasValue && key == "name":
+			spec.Name = strings.TrimSpace(value)
+		case hasValue && key == "cmdline":
+			spec.Cmdline = value
+		case hasValue && key == "user":
+			spec.User = strings.TrimSpace(value)
+		case hasValue && key == "parent":
+			spec.Parent = strings.TrimSpace(value)
+		case hasValue && key == "pidfile":
+			spec.Pidfile = strings.TrimSpace(value)
+		case hasValue && key == "count":
+			spec.Min, spec.Max, err = parseCountRange(value)
+		case hasValue && key == "cpu":
+			spec.CPUWarning, spec.CPUCritical, err = parsePercentPair(value)
+		case hasValue && key == "rss":
+			spec.RSSWarning, spec.RSSCritical, err = parseBytesPair(value)
+		case hasValue && key == "fds":
+			spec.FDsWarning, spec.FDsCritical, err = parseCountPair(value)
+		case hasValue && key == "threads":
+			spec.ThreadsWarning, spec.ThreadsCritical, err = parseCountPair(value)
+		case hasValue && key == "io":
+			spec.IOWarning, spec.IOCritical, err = parseBytesPair(value)
+		case i == 0:
+			// - The leading field may be a bare name or name=count
+			spec.Name = key
+			if hasValue {
+				spec.Min, spec.Max, err = parseCountRange(value)
+			}
+		default:
+			err = fmt.Errorf("unknown field %q", field)
+		}
+		if err != nil {
+			return ProcessSpec{}, fmt.Errorf("invalid process spec %q: %w", text, err)
+		}
+	}
+	if err := spec.Validate(); err != nil {
+		return ProcessSpec{}, err
+	}
+	return spec, nil
+}
+
+// parseCountRange parses "N" (exactly N), "MIN:MAX", "MIN:" or ":MAX"
+func parseCountRange(text string) (min, max int, err error) {
+	low, high, isRange := strings.Cut(text, ":")
+	if min, err = parseCount(low, 0); err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return min, min, nil
+	}
+	if max, err = parseCount(high, 0); err != nil {
+		return 0, 0, err
+	}
+	return min, max, nil
+}
+
+// parsePercentPair parses a WARNING:CRITICAL pair of percentages
+func parsePercentPair(text string) (warning, critical float64, err error) {
+	low, high, _ := strings.Cut(text, ":")
+	parse := func(s string) (float64, error) {
+		if s = strings.TrimSpace(s); s == "" {
+			return 0, nil
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+	if warning, err = parse(low); err != nil {
+		return 0, 0, err
+	}
+	if critical, err = parse(high); err != nil {
+		return 0, 0, err
+	}
+	return warning, critical, nil
+}
+
+// parseBytesPair parses a WARNING:CRITICAL pair of sizes, e.g. "1GiB:2GiB"
+func parseBytesPair(text string) (warning, critical ByteSize, err error) {
+	low, high, _ := strings.Cut(text, ":")
+	parse := func(s string) (ByteSize, error) {
+		if s = strings.TrimSpace(s); s == "" {
+			return 0, nil
+		}
+		return ParseByteSize(s)
+	}
+	if warning, err = parse(low); err != nil {
+		return 0, 0, err
+	}
+	if critical, err = parse(high); err != nil {
+		return 0, 0, err
+	}
+	return warning, critical, nil
+}
+
+// parseCountPair parses a WARNING:CRITICAL pair of counts
+func parseCountPair(text string) (warning, critical int, err error) {
+	low, high, _ := strings.Cut(text, ":")
+	if warning, err = parseCount(low, 0); err != nil {
+		return 0, 0, err
+	}
+	if critical, err = parseCount(high, 0); err != nil {
+		return 0, 0, err
+	}
+	return warning, critical, nil
+}
+
+// parseCount parses an instance count, returning def for an empty string
+func parseCount(text string, def int) (int, error) {
+	if text = strings.TrimSpace(text); text == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("count %q must be a non-negative integer", text)
+	}
+	return n, nil
+}
+
+// Validate checks that the spec has at least one matcher, a valid
+// cmdline pattern and a sensible count range
+func (ps ProcessSpec) Validate() error {
+	label := ps.Label()
+	if label == "" {
+		return fmt.Errorf("process spec needs a name, cmdline, user, parent or pidfile")
+	}
+	if ps.Cmdline != "" {
+		if _, err := regexp.Compile(ps.Cmdline); err != nil {
+			return fmt.Errorf("process %s: invalid cmdline pattern: %w", label, err)
+		}
+	}
+	if ps.Min < 0 || ps.Max < 0 {
+		return fmt.Errorf("process %s: counts must not be negative", label)
+	}
+	if ps.Max > 0 && ps.Max < ps.Min {
+		return fmt.Errorf("process %s: max (%d) must not be below min (%d)", label, ps.Max, ps.Min)
+	}
+	if ps.Min == 0 && ps.Max == 0 {
+		return fmt.Errorf("process %s: expects neither a minimum nor a maximum", label)
+	}
+	if err := ps.ProcessLimits.Validate(); err != nil {
+		return fmt.Errorf("process %s: %w", label, err)
+	}
+	return nil
+}
+
+// Label describes the spec by its matchers, e.g. "java user=kafka"
+func (ps ProcessSpec) Label() string {
+	parts := make([]string, 0, 5)
+	if ps.Name != "" {
+		parts = append(parts, ps.Name)
+	}
+	if ps.Cmdline != "" {
+		parts = append(parts, "cmdline="+ps.Cmdline)
+	}
+	if ps.User != "" {
+		parts = append(parts, "user="+ps.User)
+	}
+	if ps.Parent != "" {
+		parts = append(parts, "parent="+ps.Parent)
+	}
+	if ps.Pidfile != "" {
+		parts = append(parts, "pidfile="+ps.Pidfile)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Key identifies the processes a spec matches, for state kept between
+// runs. Unlike Label it is unambiguous: specs with the same Key match the
+// same processes, whatever their counts and limits.
+func (ps ProcessSpec) Key() string {
+	key, _ := json.Marshal([]string{ps.Name, ps.Cmdline, ps.User, ps.Parent, ps.Pidfile})
+	return string(key)
+}
+
+// Expected describes the expected instance count, e.g. ">= 1" or "2-4"
+func (ps ProcessSpec) Expected() string {
+	switch {
+	case ps.Max == 0:
+		return fmt.Sprintf(">= %d", ps.Min)
+	case ps.Min == ps.Max:
+		return fmt.Sprintf("= %d", ps.Min)
+	case ps.Min == 0:
+		return fmt.Sprintf("<= %d", ps.Max)
+	}
+	return fmt.Sprintf("%d-%d", ps.Min, ps.Max)
+}
+
+// GetStatus evaluates a number of running instances against the spec:
+// fewer than Min is CRITICAL, more than Max is WARNING
+func (ps ProcessSpec) GetStatus(count int) Status {
+	if count < ps.Min {
+		return StatusCritical
+	}
+	if ps.Max > 0 && count > ps.Max {
+		return StatusWarning
+	}
+	return StatusOK
+}
+
+// Validate checks that no limit is negative and that every warning
+// limit is below its critical limit
+func (pl ProcessLimits) Validate() error {
+	pairs := []struct {
+		name              string
+		warning, critical float64
+	}{
+		{"cpu", pl.CPUWarning, pl.CPUCritical},
+		{"rss", float64(pl.RSSWarning), float64(pl.RSSCritical)},
+		{"fds", float64(pl.FDsWarning), float64(pl.FDsCritical)},
+		{"threads", float64(pl.ThreadsWarning), float64(pl.ThreadsCritical)},
+		{"io", float64(pl.IOWarning), float64(pl.IOCritical)},
+	}
+	var errs []error
+	for _, p := range pairs {
+		if p.warning < 0 || p.critical < 0 {
+			errs = append(errs, fmt.Errorf("%s limits must not be negative", p.name))
+		} else if p.warning > 0 && p.critical > 0 && p.warning > p.critical {
+			errs = append(errs, fmt.Errorf("%s warning must not exceed %s critical", p.name, p.name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Evaluate checks one process instance against every configured
+// limit; the worst result wins
+func (pl ProcessLimits) Evaluate(p *ProcessInfo) Status {
+	status := StatusOK
+	status = status.Worse(evaluateLimit(p.CPUPercent, pl.CPUWarning, pl.CPUCritical))
+	status = status.Worse(evaluateLimit(float64(p.RSSBytes), float64(pl.RSSWarning), float64(pl.RSSCritical)))
+	status = status.Worse(evaluateLimit(float64(p.NumFDs), float64(pl.FDsWarning), float64(pl.FDsCritical)))
+	status = status.Worse(evaluateLimit(float64(p.NumThreads), float64(pl.ThreadsWarning), float64(pl.ThreadsCritical)))
+	io := p.ReadBytesPerSec + p.WriteBytesPerSec
+	status = status.Worse(evaluateLimit(io, float64(pl.IOWarning), float64(pl.IOCritical)))
+	return status
+}
+
+// Describe lists the configured warning limits, e.g. "cpu < 80%, fds < 1000"
+func (pl ProcessLimits) Describe() string {
+	parts := make([]string, 0, 5)
+	if limit := firstPositive(pl.CPUWarning, pl.CPUCritical); limit > 0 {
+		parts = append(parts, fmt.Sprintf("cpu < %.0f%%", limit))
+	}
+	if limit := firstPositive(float64(pl.RSSWarning), float64(pl.RSSCritical)); limit > 0 {
+		parts = append(parts, fmt.Sprintf("rss < %s", ByteSize(limit)))
+	}
+	if limit := firstPositive(float64(pl.FDsWarning), float64(pl.FDsCritical)); limit > 0 {
+		parts = append(parts, fmt.Sprintf("fds < %.0f", limit))
+	}
+	if limit := firstPositive(float64(pl.ThreadsWarning), float64(pl.ThreadsCritical)); limit > 0 {
+		parts = append(parts, fmt.Sprintf("threads < %.0f", limit))
+	}
+	if limit := firstPositive(float64(pl.IOWarning), float64(pl.IOCritical)); limit > 0 {
+		parts = append(parts, fmt.Sprintf("io < %s/s", ByteSize(limit)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// evaluateLimit is EvaluateHigher where a zero limit is disabled
+func evaluateLimit(value, warning, critical float64) Status {
+	if critical > 0 && value >= critical {
+		return StatusCritical
+	}
+	if warning > 0 && value >= warning {
+		return StatusWarning
+	}
+	return StatusOK
+}
+
+// UnmarshalJSON accepts either a spec string such as "worker=2:4" or an
+// object; an object without "min" expects at least one instance
+func (ps *ProcessSpec) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		spec, err := ParseProcessSpec(text)
+		if err != nil {
+			return err
+		}
+		*ps = spec
+		return nil
+	}
+
+	type plain ProcessSpec
+	spec := plain{Min: 1}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return err
+	}
+	*ps = ProcessSpec(spec)
+	return nil
+}
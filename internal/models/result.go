@@ -0,0 +1,40 @@
+package models
+
+// CheckResult is the evaluated outcome of a single registered check
+type CheckResult struct {
+	// Name is the key the check was registered under (also its JSON key)
+	Name   string
+	Status Status
+	// Rows are the lines shown for this check in the table output
+	Rows []ResultRow
+	// Data is the check's typed result, rendered as-is in the JSON output
+	Data interface{}
+	// Error is set when the check could not collect any data
+	Error string
+}
+
+// ResultRow is one table line of a check result
+type ResultRow struct {
+	Metric    string
+	Value     string
+	Status    Status
+	Threshold string
+}
+
+func NewCheckResult(name string) *CheckResult {
+	return &CheckResult{
+		Name:   name,
+		Status: StatusOK,
+		Rows:   make([]ResultRow, 0),
+	}
+}
+
+// AddRow appends a table row to the result
+func (cr *CheckResult) AddRow(metric, value string, status Status, threshold string) {
+	cr.Rows = append(cr.Rows, ResultRow{
+		Metric:    metric,
+		Value:     value,
+		Status:    status,
+		Threshold: threshold,
+	})
+}
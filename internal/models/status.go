@@ -0,0 +1,83 @@
+package models
+
+import "fmt"
+
+// Status is the health status of a metric, a check or the whole system.
+// Statuses are ordered from best to worst, so the larger value wins when
+// combining them. UNKNOWN ranks above OK so a check without data is never
+// hidden, but below real WARNING and CRITICAL findings.
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusUnknown
+	StatusWarning
+	StatusCritical
+)
+
+var statusNames = map[Status]string{
+	StatusOK:       "OK",
+	StatusUnknown:  "UNKNOWN",
+	StatusWarning:  "WARNING",
+	StatusCritical: "CRITICAL",
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Worse returns the more severe of s and other
+func (s Status) Worse(other Status) Status {
+	if other > s {
+		return other
+	}
+	return s
+}
+
+// ParseStatus converts a status name such as "WARNING" into a Status
+func ParseStatus(name string) (Status, error) {
+	for status, n := range statusNames {
+		if n == name {
+			return status, nil
+		}
+	}
+	return StatusUnknown, fmt.Errorf("invalid status: %s", name)
+}
+
+// MarshalText encodes the status by name, e.g. in JSON output
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a status name
+func (s *Status) UnmarshalText(text []byte) error {
+	status, err := ParseStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
+// EvaluateHigher evaluates a value where higher is worse
+func EvaluateHigher(value, warning, critical float64) Status {
+	if value >= critical {
+		return StatusCritical
+	} else if value >= warning {
+		return StatusWarning
+	}
+	return StatusOK
+}
+
+// EvaluateLower evaluates a value where lower is worse
+func EvaluateLower(value, warning, critical float64) Status {
+	if value < critical {
+		return StatusCritical
+	} else if value < warning {
+		return StatusWarning
+	}
+	return StatusOK
+}
@@ -0,0 +1,48 @@
+package models
+
+// TCPInfo holds socket counts by TCP state, ephemeral port usage and
+// listen queue overflow rates
+type TCPInfo struct {
+	// States counts IPv4 and IPv6 sockets by state name, e.g. "TIME_WAIT"
+	States map[string]int
+	// EphemeralUsed is the number of distinct local ports in the
+	// ephemeral range held by non-listening sockets
+	EphemeralUsed int
+	EphemeralLow  int
+	EphemeralHigh int
+	// Listen queue overflows and drops per second
+	ListenOverflowRate float64
+	ListenDropRate     float64
+}
+
+// GetEphemeralRange returns the number of ports in the ephemeral range
+func (ti *TCPInfo) GetEphemeralRange() int {
+	if ti.EphemeralHigh < ti.EphemeralLow {
+		return 0
+	}
+	return ti.EphemeralHigh - ti.EphemeralLow + 1
+}
+
+// GetEphemeralPercent calculates the share of ephemeral ports in use
+func (ti *TCPInfo) GetEphemeralPercent() float64 {
+	size := ti.GetEphemeralRange()
+	if size == 0 {
+		return 0.0
+	}
+	return float64(ti.EphemeralUsed) / float64(size) * 100
+}
+
+// GetTimeWaitStatus evaluates the number of TIME_WAIT sockets
+func (ti *TCPInfo) GetTimeWaitStatus(thresholds *Thresholds) Status {
+	return EvaluateHigher(float64(ti.States["TIME_WAIT"]), thresholds.TCPTimeWaitWarning, thresholds.TCPTimeWaitCritical)
+}
+
+// GetEphemeralStatus evaluates ephemeral port usage
+func (ti *TCPInfo) GetEphemeralStatus(thresholds *Thresholds) Status {
+	return EvaluateHigher(ti.GetEphemeralPercent(), thresholds.EphemeralPortWarning, thresholds.EphemeralPortCritical)
+}
+
+// GetListenOverflowStatus evaluates the listen queue overflow rate
+func (ti *TCPInfo) GetListenOverflowStatus(thresholds *Thresholds) Status {
+	return EvaluateHigher(ti.ListenOverflowRate, thresholds.ListenOverflowWarning, thresholds.ListenOverflowCritical)
+}
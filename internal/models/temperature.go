@@ -0,0 +1,49 @@
+package models
+
+// Fallback temperature limits in °C for sensors that report no limits of
+// their own when no thresholds are configured
+const (
+	DefaultTempWarning  = 80.0
+	DefaultTempCritical = 95.0
+)
+
+// TemperatureInfo holds one hardware temperature sensor reading in °C
+type TemperatureInfo struct {
+	// Source is "hwmon" or "thermal"
+	Source string
+	// Chip is the hwmon driver name or the thermal zone, e.g. "coretemp"
+	Chip  string
+	Label string
+	// Celsius is the current temperature
+	Celsius float64
+	// Max and Crit are the kernel-provided limits; zero when not reported
+	Max  float64
+	Crit float64
+}
+
+// Limits returns the warning and critical temperatures for this sensor.
+// Configured thresholds win; a threshold of zero falls back to the
+// kernel-provided limit, then to DefaultTempWarning/DefaultTempCritical.
+// The levels fall back separately, so warning is capped at critical.
+func (ti *TemperatureInfo) Limits(thresholds *Thresholds) (warning, critical float64) {
+	warning = firstPositive(thresholds.TempWarning, ti.Max, DefaultTempWarning)
+	critical = firstPositive(thresholds.TempCritical, ti.Crit, DefaultTempCritical)
+	warning = min(warning, critical)
+	return warning, critical
+}
+
+// GetStatus evaluates the temperature against the sensor's limits
+func (ti *TemperatureInfo) GetStatus(thresholds *Thresholds) Status {
+	warning, critical := ti.Limits(thresholds)
+	return EvaluateHigher(ti.Celsius, warning, critical)
+}
+
+// firstPositive returns the first value above zero
+func firstPositive(values ...float64) float64 {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
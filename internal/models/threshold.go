@@ -1,29 +1,350 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+)
+
+// Thresholds holds the warning and critical levels for every check.
+// The json tags define the keys used in configuration files.
 type Thresholds struct {
-	CPUWarning   float64
-	CPUCritical  float64
-	MemWarning   float64
-	MemCritical  float64
-	DiskWarning  float64
-	DiskCritical float64
+	CPUWarning  float64 `json:"cpu_warning"`
+	CPUCritical float64 `json:"cpu_critical"`
+	// Hot core rule: usage of the busiest single core. A critical level of
+	// 0 disables it, so one pegged core is only a WARNING by default.
+	CPUCoreWarning  float64 `json:"cpu_core_warning"`
+	CPUCoreCritical float64 `json:"cpu_core_critical"`
+	// Share of CPU time stolen by the hypervisor
+	CPUStealWarning  float64 `json:"cpu_steal_warning"`
+	CPUStealCritical float64 `json:"cpu_steal_critical"`
+	MemWarning       float64 `json:"mem_warning"`
+	MemCritical      float64 `json:"mem_critical"`
+	SwapWarning      float64 `json:"swap_warning"`
+	SwapCritical     float64 `json:"swap_critical"`
+	DiskWarning      float64 `json:"disk_warning"`
+	DiskCritical     float64 `json:"disk_critical"`
+	// Absolute free-space limits; zero disables the rule
+	DiskWarningBytes  ByteSize `json:"disk_warning_bytes"`
+	DiskCriticalBytes ByteSize `json:"disk_critical_bytes"`
+	// DiskMounts overrides the disk thresholds for matching mount points
+	DiskMounts []DiskMountThreshold `json:"disk_mounts"`
+	// Inode thresholds are used percent, like CPU and memory
+	InodeWarning  float64 `json:"inode_warning"`
+	InodeCritical float64 `json:"inode_critical"`
+	// Disk I/O: device utilization in percent and average await in ms
+	DiskUtilWarning   float64 `json:"disk_util_warning"`
+	DiskUtilCritical  float64 `json:"disk_util_critical"`
+	DiskAwaitWarning  float64 `json:"disk_await_warning"`
+	DiskAwaitCritical float64 `json:"disk_await_critical"`
+	// Network error and drop thresholds are per second, rx and tx combined
+	NetErrorWarning  float64 `json:"net_error_warning"`
+	NetErrorCritical float64 `json:"net_error_critical"`
+	NetDropWarning   float64 `json:"net_drop_warning"`
+	NetDropCritical  float64 `json:"net_drop_critical"`
+	// TCP: TIME_WAIT socket count, ephemeral ports used in percent and
+	// listen queue overflows per second
+	TCPTimeWaitWarning     float64 `json:"tcp_time_wait_warning"`
+	TCPTimeWaitCritical    float64 `json:"tcp_time_wait_critical"`
+	EphemeralPortWarning   float64 `json:"ephemeral_port_warning"`
+	EphemeralPortCritical  float64 `json:"ephemeral_port_critical"`
+	ListenOverflowWarning  float64 `json:"listen_overflow_warning"`
+	ListenOverflowCritical float64 `json:"listen_overflow_critical"`
+	// Kernel table fill levels in percent
+	FileHandleWarning  float64 `json:"file_handle_warning"`
+	FileHandleCritical float64 `json:"file_handle_critical"`
+	PIDWarning         float64 `json:"pid_warning"`
+	PIDCritical        float64 `json:"pid_critical"`
+	ConntrackWarning   float64 `json:"conntrack_warning"`
+	ConntrackCritical  float64 `json:"conntrack_critical"`
+	// Temperature thresholds in °C; zero uses each sensor's own limits
+	TempWarning  float64 `json:"temp_warning"`
+	TempCritical float64 `json:"temp_critical"`
+	// Load thresholds are load average per logical CPU
+	LoadWarning  float64 `json:"load_warning"`
+	LoadCritical float64 `json:"load_critical"`
+	// Pressure thresholds apply to the PSI "some" 10 second average
+	PSICPUWarning     float64 `json:"psi_cpu_warning"`
+	PSICPUCritical    float64 `json:"psi_cpu_critical"`
+	PSIMemoryWarning  float64 `json:"psi_memory_warning"`
+	PSIMemoryCritical float64 `json:"psi_memory_critical"`
+	PSIIOWarning      float64 `json:"psi_io_warning"`
+	PSIIOCritical     float64 `json:"psi_io_critical"`
+	// Full pressure thresholds apply to the PSI "full" 10 second average,
+	// when all non-idle tasks were stalled at once. CPU has none: its
+	// system-wide "full" line is always zero.
+	PSIMemoryFullWarning  float64 `json:"psi_memory_full_warning"`
+	PSIMemoryFullCritical float64 `json:"psi_memory_full_critical"`
+	PSIIOFullWarning      float64 `json:"psi_io_full_warning"`
+	PSIIOFullCritical     float64 `json:"psi_io_full_critical"`
+	// Restart thresholds are process restarts within the restart window
+	RestartWarning  float64 `json:"restart_warning"`
+	RestartCritical float64 `json:"restart_critical"`
+}
+
+// DiskLimits are the free-space thresholds that apply to one mount point.
+// Percentages are free percent; byte limits of zero are disabled.
+type DiskLimits struct {
+	Warning       float64  `json:"warning"`
+	Critical      float64  `json:"critical"`
+	WarningBytes  ByteSize `json:"warning_bytes"`
+	CriticalBytes ByteSize `json:"critical_bytes"`
+}
+
+// DiskMountThreshold overrides the disk thresholds for one mount point
+// or for every mount point matching a glob pattern
+type DiskMountThreshold struct {
+	Mount string `json:"mount"`
+	DiskLimits
 }
 
 func NewDefaultThresholds() *Thresholds {
 	// - Create new Thresholds
 	// - Set CPUWarning = 80.0
 	// - Set CPUCritical = 90.0
+	// - Set CPUCoreWarning = 95.0, CPUCoreCritical = 0 (WARNING only)
+	// - Set CPUStealWarning = 10.0, CPUStealCritical = 20.0
 	// - Set MemWarning = 75.0
 	// - Set MemCritical = 85.0
+	// - Set SwapWarning = 50.0, SwapCritical = 80.0
 	// - Set DiskWarning = 20.0 (20% free)
 	// - Set DiskCritical = 10.0 (10% free)
+	// - Set InodeWarning = 80.0
+	// - Set InodeCritical = 90.0
+	// - Set DiskUtilWarning = 80.0, DiskUtilCritical = 95.0
+	// - Set DiskAwaitWarning = 50.0, DiskAwaitCritical = 200.0 (ms)
+	// - Set NetErrorWarning = 1.0, NetErrorCritical = 10.0 (per second)
+	// - Set NetDropWarning = 10.0, NetDropCritical = 100.0 (per second)
+	// - Set TCPTimeWaitWarning = 10000, TCPTimeWaitCritical = 30000 (sockets)
+	// - Set EphemeralPortWarning = 70.0, EphemeralPortCritical = 90.0
+	// - Set ListenOverflowWarning = 1.0, ListenOverflowCritical = 10.0 (per second)
+	// - Set FileHandle, PID and Conntrack warning = 80.0, critical = 90.0
+	// - Leave TempWarning and TempCritical at 0 (sensor limits)
+	// - Set LoadWarning = 1.0, LoadCritical = 2.0 (per logical CPU)
+	// - Set PSI cpu 20/40, memory 10/20, io 20/40 (percent stalled)
+	// - Set PSI full memory 5/10, io 10/20 (percent stalled)
+	// - Set RestartWarning = 2, RestartCritical = 5 (restarts per window)
 	// - Return pointer to struct
 	return &Thresholds{
-		CPUWarning:   80.0,
-		CPUCritical:  90.0,
-		MemWarning:   75.0,
-		MemCritical:  85.0,
-		DiskWarning:  20.0,
-		DiskCritical: 10.0,
+		CPUWarning:             80.0,
+		CPUCritical:            90.0,
+		CPUCoreWarning:         95.0,
+		CPUCoreCritical:        0,
+		CPUStealWarning:        10.0,
+		CPUStealCritical:       20.0,
+		MemWarning:             75.0,
+		MemCritical:            85.0,
+		SwapWarning:            50.0,
+		SwapCritical:           80.0,
+		DiskWarning:            20.0,
+		DiskCritical:           10.0,
+		InodeWarning:           80.0,
+		InodeCritical:          90.0,
+		DiskUtilWarning:        80.0,
+		DiskUtilCritical:       95.0,
+		DiskAwaitWarning:       50.0,
+		DiskAwaitCritical:      200.0,
+		NetErrorWarning:        1.0,
+		NetErrorCritical:       10.0,
+		NetDropWarning:         10.0,
+		NetDropCritical:        100.0,
+		TCPTimeWaitWarning:     10000,
+		TCPTimeWaitCritical:    30000,
+		EphemeralPortWarning:   70.0,
+		EphemeralPortCritical:  90.0,
+		ListenOverflowWarning:  1.0,
+		ListenOverflowCritical: 10.0,
+		FileHandleWarning:      80.0,
+		FileHandleCritical:     90.0,
+		PIDWarning:             80.0,
+		PIDCritical:            90.0,
+		ConntrackWarning:       80.0,
+		ConntrackCritical:      90.0,
+		LoadWarning:            1.0,
+		LoadCritical:           2.0,
+		PSICPUWarning:          20.0,
+		PSICPUCritical:         40.0,
+		PSIMemoryWarning:       10.0,
+		PSIMemoryCritical:      20.0,
+		PSIIOWarning:           20.0,
+		PSIIOCritical:          40.0,
+		PSIMemoryFullWarning:   5.0,
+		PSIMemoryFullCritical:  10.0,
+		PSIIOFullWarning:       10.0,
+		PSIIOFullCritical:      20.0,
+		RestartWarning:         2,
+		RestartCritical:        5,
+	}
+}
+
+// ForDisk returns the disk thresholds for a mount point. An exact mount
+// match wins over a glob pattern; otherwise the first matching pattern
+// wins, falling back to the global values. Byte limits an override leaves
+// unset are inherited from the global byte limits.
+func (t *Thresholds) ForDisk(mountPoint string) DiskLimits {
+	for _, m := range t.DiskMounts {
+		if m.Mount == mountPoint {
+			return t.withDiskBytes(m.DiskLimits)
+		}
+	}
+	for _, m := range t.DiskMounts {
+		if MatchPattern(m.Mount, mountPoint) {
+			return t.withDiskBytes(m.DiskLimits)
+		}
+	}
+	return DiskLimits{
+		Warning:       t.DiskWarning,
+		Critical:      t.DiskCritical,
+		WarningBytes:  t.DiskWarningBytes,
+		CriticalBytes: t.DiskCriticalBytes,
+	}
+}
+
+// withDiskBytes fills the zero byte limits of a mount override from the
+// global byte limits
+func (t *Thresholds) withDiskBytes(limits DiskLimits) DiskLimits {
+	if limits.WarningBytes == 0 {
+		limits.WarningBytes = t.DiskWarningBytes
+	}
+	if limits.CriticalBytes == 0 {
+		limits.CriticalBytes = t.DiskCriticalBytes
+	}
+	return limits
+}
+
+// ForPressure returns the warning and critical levels for a PSI resource
+// (cpu, memory or io); unknown resources are never flagged
+func (t *Thresholds) ForPressure(resource string) (warning, critical float64) {
+	switch resource {
+	case "cpu":
+		return t.PSICPUWarning, t.PSICPUCritical
+	case "memory":
+		return t.PSIMemoryWarning, t.PSIMemoryCritical
+	case "io":
+		return t.PSIIOWarning, t.PSIIOCritical
+	}
+	return math.Inf(1), math.Inf(1)
+}
+
+// ForPressureFull returns the warning and critical levels for the "full"
+// line of a PSI resource; cpu and unknown resources are never flagged
+func (t *Thresholds) ForPressureFull(resource string) (warning, critical float64) {
+	switch resource {
+	case "memory":
+		return t.PSIMemoryFullWarning, t.PSIMemoryFullCritical
+	case "io":
+		return t.PSIIOFullWarning, t.PSIIOFullCritical
+	}
+	return math.Inf(1), math.Inf(1)
+}
+
+// Validate checks that every threshold is a percentage between 0 and 100
+// and that warning and critical levels are not inverted. All problems are
+// returned together.
+func (t *Thresholds) Validate() error {
+	var errs []error
+
+	// - Higher is worse for CPU and memory: warning must not exceed critical
+	errs = append(errs, validatePair("cpu", t.CPUWarning, t.CPUCritical, true)...)
+	if t.CPUCoreCritical == 0 {
+		errs = append(errs, validatePair("cpu-core", t.CPUCoreWarning, t.CPUCoreWarning, true)...)
+	} else {
+		errs = append(errs, validatePair("cpu-core", t.CPUCoreWarning, t.CPUCoreCritical, true)...)
+	}
+	errs = append(errs, validatePair("cpu-steal", t.CPUStealWarning, t.CPUStealCritical, true)...)
+	errs = append(errs, validatePair("mem", t.MemWarning, t.MemCritical, true)...)
+	errs = append(errs, validatePair("swap", t.SwapWarning, t.SwapCritical, true)...)
+	// - Disk thresholds are free percent, so lower is worse: warning must not be below critical
+	errs = append(errs, validatePair("disk", t.DiskWarning, t.DiskCritical, false)...)
+	errs = append(errs, validateBytesPair("disk", t.DiskWarningBytes, t.DiskCriticalBytes)...)
+	for _, m := range t.DiskMounts {
+		name := fmt.Sprintf("disk[%s]", m.Mount)
+		if _, err := filepath.Match(m.Mount, ""); m.Mount == "" || err != nil {
+			errs = append(errs, fmt.Errorf("disk mount pattern %q is invalid", m.Mount))
+		}
+		errs = append(errs, validatePair(name, m.Warning, m.Critical, false)...)
+		// - Validate the effective byte limits, including inherited global ones
+		limits := t.withDiskBytes(m.DiskLimits)
+		errs = append(errs, validateBytesPair(name, limits.WarningBytes, limits.CriticalBytes)...)
+	}
+	errs = append(errs, validatePair("inode", t.InodeWarning, t.InodeCritical, true)...)
+	errs = append(errs, validatePair("disk-util", t.DiskUtilWarning, t.DiskUtilCritical, true)...)
+	errs = append(errs, validateRangePair("disk-await", t.DiskAwaitWarning, t.DiskAwaitCritical, math.Inf(1), true)...)
+	errs = append(errs, validateRangePair("net-error", t.NetErrorWarning, t.NetErrorCritical, math.Inf(1), true)...)
+	errs = append(errs, validateRangePair("net-drop", t.NetDropWarning, t.NetDropCritical, math.Inf(1), true)...)
+	errs = append(errs, validateRangePair("tcp-time-wait", t.TCPTimeWaitWarning, t.TCPTimeWaitCritical, math.Inf(1), true)...)
+	errs = append(errs, validatePair("ephemeral-port", t.EphemeralPortWarning, t.EphemeralPortCritical, true)...)
+	errs = append(errs, validateRangePair("listen-overflow", t.ListenOverflowWarning, t.ListenOverflowCritical, math.Inf(1), true)...)
+	errs = append(errs, validatePair("file-handle", t.FileHandleWarning, t.FileHandleCritical, true)...)
+	errs = append(errs, validatePair("pid", t.PIDWarning, t.PIDCritical, true)...)
+	errs = append(errs, validatePair("conntrack", t.ConntrackWarning, t.ConntrackCritical, true)...)
+	// - Temperature thresholds of zero fall back to sensor limits, so
+	//   the pair is only compared when both are set
+	if t.TempWarning > 0 && t.TempCritical > 0 {
+		errs = append(errs, validateRangePair("temp", t.TempWarning, t.TempCritical, math.Inf(1), true)...)
+	} else {
+		errs = append(errs, validateNonNegative("temp-warning", t.TempWarning)...)
+		errs = append(errs, validateNonNegative("temp-critical", t.TempCritical)...)
+	}
+	errs = append(errs, validateRangePair("load", t.LoadWarning, t.LoadCritical, math.Inf(1), true)...)
+	errs = append(errs, validatePair("psi-cpu", t.PSICPUWarning, t.PSICPUCritical, true)...)
+	errs = append(errs, validatePair("psi-memory", t.PSIMemoryWarning, t.PSIMemoryCritical, true)...)
+	errs = append(errs, validatePair("psi-io", t.PSIIOWarning, t.PSIIOCritical, true)...)
+	errs = append(errs, validatePair("psi-memory-full", t.PSIMemoryFullWarning, t.PSIMemoryFullCritical, true)...)
+	errs = append(errs, validatePair("psi-io-full", t.PSIIOFullWarning, t.PSIIOFullCritical, true)...)
+	errs = append(errs, validateRangePair("restart", t.RestartWarning, t.RestartCritical, math.Inf(1), true)...)
+
+	return errors.Join(errs...)
+}
+
+// validatePair validates a warning/critical percentage pair
+func validatePair(name string, warning, critical float64, higherIsWorse bool) []error {
+	return validateRangePair(name, warning, critical, 100, higherIsWorse)
+}
+
+// validateRangePair validates a warning/critical pair whose values must
+// lie between 0 and max (which may be +Inf)
+func validateRangePair(name string, warning, critical, max float64, higherIsWorse bool) []error {
+	var errs []error
+	for _, v := range []struct {
+		level string
+		value float64
+	}{{"warning", warning}, {"critical", critical}} {
+		if math.IsNaN(v.value) || v.value < 0 || v.value > max {
+			if math.IsInf(max, 1) {
+				errs = append(errs, fmt.Errorf("%s-%s must be a non-negative number, got %v", name, v.level, v.value))
+			} else {
+				errs = append(errs, fmt.Errorf("%s-%s must be between 0 and %v, got %v", name, v.level, max, v.value))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if higherIsWorse && warning > critical {
+		errs = append(errs, fmt.Errorf("%s-warning (%v) must not be above %s-critical (%v)", name, warning, name, critical))
+	}
+	if !higherIsWorse && warning < critical {
+		errs = append(errs, fmt.Errorf("%s-warning (%v%% free) must not be below %s-critical (%v%% free)", name, warning, name, critical))
+	}
+	return errs
+}
+
+// validateNonNegative validates a single threshold that must not be negative
+func validateNonNegative(name string, value float64) []error {
+	if math.IsNaN(value) || value < 0 {
+		return []error{fmt.Errorf("%s must be a non-negative number, got %v", name, value)}
+	}
+	return nil
+}
+
+// validateBytesPair validates a warning/critical free-bytes pair, where
+// lower is worse and zero disables the rule
+func validateBytesPair(name string, warning, critical ByteSize) []error {
+	if warning != 0 && critical != 0 && warning < critical {
+		return []error{fmt.Errorf("%s-warning-bytes (%s free) must not be below %s-critical-bytes (%s free)", name, warning, name, critical)}
 	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 
@@ -9,146 +10,65 @@ import (
 
 // JSONOutput represents the JSON output structure
 type JSONOutput struct {
-	Timestamp     string      `json:"timestamp"`
-	OverallStatus string      `json:"overall_status"`
-	Metrics       MetricsJSON `json:"metrics"`
+	Timestamp     string        `json:"timestamp"`
+	OverallStatus models.Status `json:"overall_status"`
+	Metrics       MetricsJSON   `json:"metrics"`
 }
 
+// MetricsJSON is a JSON object keyed by check name that keeps
+// the order in which checks were registered
 type MetricsJSON struct {
-	CPU       CPUMetric       `json:"cpu"`
-	Memory    MemoryMetric    `json:"memory"`
-	Disks     []DiskMetric    `json:"disks"`
-	Processes []ProcessMetric `json:"processes,omitempty"`
+	keys   []string
+	values map[string]interface{}
 }
 
-type CPUMetric struct {
-	Percent float64 `json:"percent"`
-	Status  string  `json:"status"`
-}
-
-type MemoryMetric struct {
-	UsedBytes  uint64  `json:"used_bytes"`
-	TotalBytes uint64  `json:"total_bytes"`
-	Percent    float64 `json:"percent"`
-	Status     string  `json:"status"`
-}
-
-type DiskMetric struct {
-	MountPoint  string  `json:"mount_point"`
-	UsedBytes   uint64  `json:"used_bytes"`
-	TotalBytes  uint64  `json:"total_bytes"`
-	UsedPercent float64 `json:"used_percent"`
-	FreePercent float64 `json:"free_percent"`
-	Status      string  `json:"status"`
+// Set adds or replaces the value stored under key
+func (mj *MetricsJSON) Set(key string, value interface{}) {
+	if mj.values == nil {
+		mj.values = make(map[string]interface{})
+	}
+	if _, ok := mj.values[key]; !ok {
+		mj.keys = append(mj.keys, key)
+	}
+	mj.values[key] = value
 }
 
-type ProcessMetric struct {
-	Name          string  `json:"name"`
-	PID           int32   `json:"pid"`
-	Status        string  `json:"status"`
-	MemoryPercent float64 `json:"memory_percent"`
+// MarshalJSON encodes the entries in insertion order
+func (mj MetricsJSON) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range mj.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(mj.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
 }
 
 // PrintJSON displays metrics in JSON format
-func PrintJSON(metrics *models.SystemMetrics, thresholds *models.Thresholds) {
+func PrintJSON(metrics *models.SystemMetrics, overallStatus models.Status) {
 	// Build base JSON output
 	jsonOutput := JSONOutput{
-		Timestamp: metrics.CheckTime.Format("2006-01-02T15:04:05Z"),
-	}
-
-	// Determine overall status (mirror logic from checker.GetOverallStatus)
-	overall := "OK"
-
-	// CPU critical check
-	if metrics.CPUPercent >= thresholds.CPUCritical {
-		overall = "CRITICAL"
-	} else {
-		// Memory checks and disk critical checks
-		memPercent := metrics.GetMemoryPercent()
-		if memPercent >= thresholds.MemCritical {
-			overall = "CRITICAL"
-		} else {
-			// Any disk critical?
-			for _, d := range metrics.Disks {
-				if d.GetStatus(thresholds) == "CRITICAL" {
-					overall = "CRITICAL"
-					break
-				}
-			}
-			// If not critical, check warnings
-			if overall != "CRITICAL" {
-				if metrics.CPUPercent >= thresholds.CPUWarning || memPercent >= thresholds.MemWarning {
-					overall = "WARNING"
-				} else {
-					for _, d := range metrics.Disks {
-						if d.GetStatus(thresholds) == "WARNING" {
-							overall = "WARNING"
-							break
-						}
-					}
-				}
-			}
-		}
+		Timestamp:     metrics.CheckTime.Format("2006-01-02T15:04:05Z"),
+		OverallStatus: overallStatus,
 	}
 
-	jsonOutput.OverallStatus = overall
-
-	// Build metrics JSON
+	// One entry per registered check, in registration order
 	var mj MetricsJSON
-
-	// CPU
-	cpuStatus := "OK"
-	if metrics.CPUPercent >= thresholds.CPUCritical {
-		cpuStatus = "CRITICAL"
-	} else if metrics.CPUPercent >= thresholds.CPUWarning {
-		cpuStatus = "WARNING"
-	}
-	mj.CPU = CPUMetric{
-		Percent: metrics.CPUPercent,
-		Status:  cpuStatus,
-	}
-
-	// Memory
-	memPercent := metrics.GetMemoryPercent()
-	memStatus := "OK"
-	if memPercent >= thresholds.MemCritical {
-		memStatus = "CRITICAL"
-	} else if memPercent >= thresholds.MemWarning {
-		memStatus = "WARNING"
-	}
-	mj.Memory = MemoryMetric{
-		UsedBytes:  metrics.MemoryUsed,
-		TotalBytes: metrics.MemoryTotal,
-		Percent:    memPercent,
-		Status:     memStatus,
-	}
-
-	// Disks
-	mj.Disks = make([]DiskMetric, 0, len(metrics.Disks))
-	for _, d := range metrics.Disks {
-		dm := DiskMetric{
-			MountPoint:  d.MountPoint,
-			UsedBytes:   d.UsedBytes,
-			TotalBytes:  d.TotalBytes,
-			UsedPercent: d.GetUsedPercent(),
-			FreePercent: d.GetFreePercent(),
-			Status:      d.GetStatus(thresholds),
-		}
-		mj.Disks = append(mj.Disks, dm)
-	}
-
-	// Processes (optional)
-	if len(metrics.Processes) > 0 {
-		mj.Processes = make([]ProcessMetric, 0, len(metrics.Processes))
-		for _, p := range metrics.Processes {
-			pm := ProcessMetric{
-				Name:          p.Name,
-				PID:           p.PID,
-				Status:        p.Status,
-				MemoryPercent: p.MemoryPercent,
-			}
-			mj.Processes = append(mj.Processes, pm)
-		}
+	for _, r := range metrics.Results {
+		mj.Set(r.Name, r.Data)
 	}
 
 	jsonOutput.Metrics = mj
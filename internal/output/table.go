@@ -10,7 +10,7 @@ import (
 )
 
 // PrintTable displays metrics in table format
-func PrintTable(metrics *models.SystemMetrics, thresholds *models.Thresholds) {
+func PrintTable(metrics *models.SystemMetrics, overallStatus models.Status) {
 	// Print header box
 	fmt.Println("╔════════════════════════════════════════════════════════════════╗")
 	fmt.Println("║   SYSTEM HEALTH CHECK REPORT                                   ║")
@@ -27,105 +27,31 @@ func PrintTable(metrics *models.SystemMetrics, thresholds *models.Thresholds) {
 	// Add a simple divider row to visually separate the header from content.
 	table.Append([]string{"------", "------", "------", "------"})
 
-	// Track overall severity: 0=OK,1=WARNING,2=CRITICAL
-	overallSeverity := 0
-	incSeverity := func(s int) {
-		if s > overallSeverity {
-			overallSeverity = s
+	// One or more rows per registered check, in registration order
+	for _, r := range metrics.Results {
+		for _, row := range r.Rows {
+			table.Append([]string{row.Metric, row.Value, colorizeStatus(row.Status), row.Threshold})
 		}
 	}
 
-	// CPU row
-	cpuValue := fmt.Sprintf("%.2f%%", metrics.CPUPercent)
-	cpuStatusColored := getStatus(metrics.CPUPercent, thresholds.CPUWarning, thresholds.CPUCritical)
-	// Determine raw CPU severity for overall computation
-	if metrics.CPUPercent >= thresholds.CPUCritical {
-		incSeverity(2)
-	} else if metrics.CPUPercent >= thresholds.CPUWarning {
-		incSeverity(1)
-	}
-	cpuThreshold := fmt.Sprintf("< %.0f%%", thresholds.CPUWarning)
-	table.Append([]string{"CPU Usage", cpuValue, cpuStatusColored, cpuThreshold})
-
-	// Memory row
-	memPercent := metrics.GetMemoryPercent()
-	usedGB := bytesToGB(metrics.MemoryUsed)
-	totalGB := bytesToGB(metrics.MemoryTotal)
-	memValue := fmt.Sprintf("%.2fGB / %.2fGB (%.1f%%)", usedGB, totalGB, memPercent)
-	memStatusColored := getStatus(memPercent, thresholds.MemWarning, thresholds.MemCritical)
-	// Raw memory severity
-	if memPercent >= thresholds.MemCritical {
-		incSeverity(2)
-	} else if memPercent >= thresholds.MemWarning {
-		incSeverity(1)
-	}
-	memThreshold := fmt.Sprintf("< %.0f%%", thresholds.MemWarning)
-	table.Append([]string{"Memory Usage", memValue, memStatusColored, memThreshold})
-
-	// Disks
-	for _, d := range metrics.Disks {
-		used := bytesToGB(d.UsedBytes)
-		total := bytesToGB(d.TotalBytes)
-		usedPercent := d.GetUsedPercent()
-		diskValue := fmt.Sprintf("%.2fGB / %.2fGB (%.1f%% used)", used, total, usedPercent)
-		// Disk.GetStatus uses free percent vs thresholds, so it returns raw status string ("OK","WARNING","CRITICAL")
-		diskRawStatus := d.GetStatus(thresholds)
-		diskStatusColored := colorizeStatus(diskRawStatus)
-		// Determine severity from raw status
-		switch diskRawStatus {
-		case "CRITICAL":
-			incSeverity(2)
-		case "WARNING":
-			incSeverity(1)
-		}
-		diskThreshold := fmt.Sprintf("< %.0f%% free", thresholds.DiskWarning)
-		table.Append([]string{fmt.Sprintf("Disk %s", d.MountPoint), diskValue, diskStatusColored, diskThreshold})
-	}
-
 	// Render table
 	table.Render()
 
 	// Overall status
-	var overall string
-	switch overallSeverity {
-	case 2:
-		overall = colorizeStatus("CRITICAL")
-	case 1:
-		overall = colorizeStatus("WARNING")
-	default:
-		overall = colorizeStatus("OK")
-	}
-	fmt.Printf("\nOverall Status: %s\n", overall)
-}
-
-// getStatus returns a colorized status string based on numeric value and thresholds.
-// value is compared against warning and critical thresholds (higher is worse).
-func getStatus(value, warning, critical float64) string {
-	if value >= critical {
-		return colorizeStatus("CRITICAL")
-	} else if value >= warning {
-		return colorizeStatus("WARNING")
-	} else {
-		return colorizeStatus("OK")
-	}
+	fmt.Printf("\nOverall Status: %s\n", colorizeStatus(overallStatus))
 }
 
-func colorizeStatus(status string) string {
+func colorizeStatus(status models.Status) string {
 	switch status {
-	case "CRITICAL":
+	case models.StatusCritical:
 		return color.RedString("🔴 CRITICAL")
-	case "WARNING":
+	case models.StatusWarning:
 		return color.YellowString("⚠️  WARNING")
-	case "OK":
+	case models.StatusOK:
 		return color.GreenString("✅ OK")
+	case models.StatusUnknown:
+		return color.MagentaString("❔ UNKNOWN")
 	default:
-		return status
-	}
-}
-
-func bytesToGB(bytes uint64) float64 {
-	if bytes == 0 {
-		return 0.0
+		return status.String()
 	}
-	return float64(bytes) / 1024.0 / 1024.0 / 1024.0
 }
@@ -1,71 +1,328 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/andinianst93/system-health-checker/internal/checker"
+	"github.com/andinianst93/system-health-checker/internal/config"
 	"github.com/andinianst93/system-health-checker/internal/models"
 	"github.com/andinianst93/system-health-checker/internal/output"
 )
 
+// envPrefix is prepended to a flag name to form its environment variable,
+// e.g. HEALTHCHECKER_CPU_WARNING for -cpu-warning
+const envPrefix = "HEALTHCHECKER_"
+
 func main() {
 	// CLI flags
+	configPath := flag.String("config", "", "Path to a YAML, TOML or JSON config file (optional)")
 	format := flag.String("format", "table", "Output format (table|json)")
-	processName := flag.String("process", "", "Check specific process by name (optional)")
+	checks := flag.String("checks", "", "Comma-separated list of checks to run (default: all)")
 
 	cpuWarning := flag.Float64("cpu-warning", -1.0, "CPU warning threshold (percent, optional)")
 	cpuCritical := flag.Float64("cpu-critical", -1.0, "CPU critical threshold (percent, optional)")
+	cpuCoreWarning := flag.Float64("cpu-core-warning", -1.0, "Hottest single core warning threshold (percent, optional)")
+	cpuCoreCritical := flag.Float64("cpu-core-critical", -1.0, "Hottest single core critical threshold (percent, 0 = WARNING only, optional)")
+	cpuStealWarning := flag.Float64("cpu-steal-warning", -1.0, "CPU steal time warning threshold (percent, optional)")
+	cpuStealCritical := flag.Float64("cpu-steal-critical", -1.0, "CPU steal time critical threshold (percent, optional)")
+	cpuInterval := flag.Duration("cpu-interval", checker.DefaultCPUInterval, "CPU sampling window (0 = average since boot)")
+	loadWarning := flag.Float64("load-warning", -1.0, "Load average warning threshold (per logical CPU, optional)")
+	loadCritical := flag.Float64("load-critical", -1.0, "Load average critical threshold (per logical CPU, optional)")
 	memWarning := flag.Float64("mem-warning", -1.0, "Memory warning threshold (percent, optional)")
 	memCritical := flag.Float64("mem-critical", -1.0, "Memory critical threshold (percent, optional)")
+	swapWarning := flag.Float64("swap-warning", -1.0, "Swap warning threshold (percent, optional)")
+	swapCritical := flag.Float64("swap-critical", -1.0, "Swap critical threshold (percent, optional)")
+	swapInterval := flag.Duration("swap-interval", checker.DefaultSwapInterval, "Swap activity sampling window (0 = no rates)")
+	psiCPUWarning := flag.Float64("psi-cpu-warning", -1.0, "CPU pressure warning threshold (some avg10 percent, optional)")
+	psiCPUCritical := flag.Float64("psi-cpu-critical", -1.0, "CPU pressure critical threshold (some avg10 percent, optional)")
+	psiMemoryWarning := flag.Float64("psi-memory-warning", -1.0, "Memory pressure warning threshold (some avg10 percent, optional)")
+	psiMemoryCritical := flag.Float64("psi-memory-critical", -1.0, "Memory pressure critical threshold (some avg10 percent, optional)")
+	psiIOWarning := flag.Float64("psi-io-warning", -1.0, "I/O pressure warning threshold (some avg10 percent, optional)")
+	psiIOCritical := flag.Float64("psi-io-critical", -1.0, "I/O pressure critical threshold (some avg10 percent, optional)")
+	psiMemoryFullWarning := flag.Float64("psi-memory-full-warning", -1.0, "Memory pressure warning threshold (full avg10 percent, optional)")
+	psiMemoryFullCritical := flag.Float64("psi-memory-full-critical", -1.0, "Memory pressure critical threshold (full avg10 percent, optional)")
+	psiIOFullWarning := flag.Float64("psi-io-full-warning", -1.0, "I/O pressure warning threshold (full avg10 percent, optional)")
+	psiIOFullCritical := flag.Float64("psi-io-full-critical", -1.0, "I/O pressure critical threshold (full avg10 percent, optional)")
 	diskWarning := flag.Float64("disk-warning", -1.0, "Disk warning threshold (free percent, optional)")
 	diskCritical := flag.Float64("disk-critical", -1.0, "Disk critical threshold (free percent, optional)")
+	inodeWarning := flag.Float64("inode-warning", -1.0, "Inode warning threshold (used percent, optional)")
+	inodeCritical := flag.Float64("inode-critical", -1.0, "Inode critical threshold (used percent, optional)")
+	diskUtilWarning := flag.Float64("disk-util-warning", -1.0, "Disk utilization warning threshold (percent busy, optional)")
+	diskUtilCritical := flag.Float64("disk-util-critical", -1.0, "Disk utilization critical threshold (percent busy, optional)")
+	diskAwaitWarning := flag.Float64("disk-await-warning", -1.0, "Disk average await warning threshold (milliseconds, optional)")
+	diskAwaitCritical := flag.Float64("disk-await-critical", -1.0, "Disk average await critical threshold (milliseconds, optional)")
+	diskIOInterval := flag.Duration("diskio-interval", checker.DefaultDiskIOInterval, "Disk I/O sampling window (0 = average since boot)")
+	netErrorWarning := flag.Float64("net-error-warning", -1.0, "Network error rate warning threshold (per second, optional)")
+	netErrorCritical := flag.Float64("net-error-critical", -1.0, "Network error rate critical threshold (per second, optional)")
+	netDropWarning := flag.Float64("net-drop-warning", -1.0, "Network drop rate warning threshold (per second, optional)")
+	netDropCritical := flag.Float64("net-drop-critical", -1.0, "Network drop rate critical threshold (per second, optional)")
+	netInterval := flag.Duration("net-interval", checker.DefaultNetworkInterval, "Network sampling window (0 = average since boot)")
+	requiredInterfaces := flag.String("require-interfaces", "", "Comma-separated network interfaces that must be up (optional)")
+	tcpTimeWaitWarning := flag.Float64("tcp-time-wait-warning", -1.0, "TIME_WAIT socket count warning threshold (optional)")
+	tcpTimeWaitCritical := flag.Float64("tcp-time-wait-critical", -1.0, "TIME_WAIT socket count critical threshold (optional)")
+	ephemeralPortWarning := flag.Float64("ephemeral-port-warning", -1.0, "Ephemeral port usage warning threshold (percent, optional)")
+	ephemeralPortCritical := flag.Float64("ephemeral-port-critical", -1.0, "Ephemeral port usage critical threshold (percent, optional)")
+	listenOverflowWarning := flag.Float64("listen-overflow-warning", -1.0, "Listen queue overflow warning threshold (per second, optional)")
+	listenOverflowCritical := flag.Float64("listen-overflow-critical", -1.0, "Listen queue overflow critical threshold (per second, optional)")
+	tcpInterval := flag.Duration("tcp-interval", checker.DefaultTCPInterval, "TCP counter sampling window (0 = average since boot)")
+	processInterval := flag.Duration("process-interval", checker.DefaultProcessInterval, "Process CPU and I/O sampling window (0 = average over process lifetime)")
+	processStateFile := flag.String("process-state-file", "", "File that keeps process start times between runs, enables restart detection (optional)")
+	restartWindow := flag.Duration("restart-window", checker.DefaultRestartWindow, "How far back process restarts are counted")
+	restartWarning := flag.Float64("restart-warning", -1.0, "Process restart warning threshold (restarts per window, optional)")
+	restartCritical := flag.Float64("restart-critical", -1.0, "Process restart critical threshold (restarts per window, optional)")
+	listening := flag.String("listening", "", "Comma-separated TCP addresses that must be listening, e.g. 127.0.0.1:5432 (optional)")
+	notListening := flag.String("not-listening", "", "Comma-separated TCP addresses nothing may listen on, e.g. 0.0.0.0:6379 (optional)")
+	fileHandleWarning := flag.Float64("file-handle-warning", -1.0, "File handle usage warning threshold (percent, optional)")
+	fileHandleCritical := flag.Float64("file-handle-critical", -1.0, "File handle usage critical threshold (percent, optional)")
+	pidWarning := flag.Float64("pid-warning", -1.0, "Task count warning threshold (percent of pid_max/threads-max, optional)")
+	pidCritical := flag.Float64("pid-critical", -1.0, "Task count critical threshold (percent of pid_max/threads-max, optional)")
+	conntrackWarning := flag.Float64("conntrack-warning", -1.0, "Conntrack table warning threshold (percent, optional)")
+	conntrackCritical := flag.Float64("conntrack-critical", -1.0, "Conntrack table critical threshold (percent, optional)")
+	tempWarning := flag.Float64("temp-warning", -1.0, "Temperature warning threshold (°C, 0 = sensor limits, optional)")
+	tempCritical := flag.Float64("temp-critical", -1.0, "Temperature critical threshold (°C, 0 = sensor limits, optional)")
+	requiredMounts := flag.String("require-mounts", "", "Comma-separated mount points that must be present (optional)")
+	writableMounts := flag.String("writable-mounts", "", "Comma-separated mount patterns that must not be read-only (default: /, /home, /srv, /var and /data with everything below them; empty disables)")
+	var diskWarningBytes, diskCriticalBytes models.ByteSize
+	flag.Var(&diskWarningBytes, "disk-warning-free", "Disk warning threshold as free space, e.g. 5GiB (optional)")
+	flag.Var(&diskCriticalBytes, "disk-critical-free", "Disk critical threshold as free space, e.g. 2GiB (optional)")
+
+	procRoot := flag.String("proc-root", checker.DefaultProcRoot, "Where procfs is mounted")
+	sysRoot := flag.String("sys-root", checker.DefaultSysRoot, "Where sysfs is mounted")
+
+	timeout := flag.Duration("timeout", checker.DefaultCheckTimeout, "Timeout for each check")
+	checkTimeouts := make(checkTimeoutFlag)
+	flag.Var(checkTimeouts, "check-timeout", "Timeout for a single check as name=duration (repeatable)")
+	var processSpecs processSpecFlag
+	flag.Var(&processSpecs, "process", "Process that must be running, as name, name=N or name=MIN:MAX instances, plus ;-separated cmdline=, user=, parent=, pidfile= or count= fields and cpu=, rss=, fds=, threads= or io= limits as WARNING:CRITICAL (repeatable)")
 
 	flag.Parse()
 
-	// Build thresholds starting from defaults, then override any provided flags
-	thresholds := models.NewDefaultThresholds()
-	if *cpuWarning >= 0 {
-		thresholds.CPUWarning = *cpuWarning
+	// Environment variables override flags
+	if err := applyEnv(); err != nil {
+		fmt.Fprintln(os.Stderr, "invalid environment:", err)
+		os.Exit(3)
 	}
-	if *cpuCritical >= 0 {
-		thresholds.CPUCritical = *cpuCritical
+
+	// Start from the config file (or defaults), then override with any flags that were set
+	cfg := config.NewDefaultConfig()
+	if *configPath != "" {
+		loaded, err := config.Load(*configPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to load config:", err)
+			os.Exit(3)
+		}
+		cfg = loaded
 	}
-	if *memWarning >= 0 {
-		thresholds.MemWarning = *memWarning
+	thresholds := cfg.Thresholds
+	processSet := false
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "format":
+			cfg.Format = *format
+		case "process":
+			cfg.Processes = processSpecs
+			processSet = true
+		case "checks":
+			cfg.Checks = splitList(*checks)
+		case "cpu-warning":
+			thresholds.CPUWarning = *cpuWarning
+		case "cpu-critical":
+			thresholds.CPUCritical = *cpuCritical
+		case "cpu-core-warning":
+			thresholds.CPUCoreWarning = *cpuCoreWarning
+		case "cpu-core-critical":
+			thresholds.CPUCoreCritical = *cpuCoreCritical
+		case "cpu-steal-warning":
+			thresholds.CPUStealWarning = *cpuStealWarning
+		case "cpu-steal-critical":
+			thresholds.CPUStealCritical = *cpuStealCritical
+		case "cpu-interval":
+			cfg.CPU.Interval = models.Duration(*cpuInterval)
+		case "load-warning":
+			thresholds.LoadWarning = *loadWarning
+		case "load-critical":
+			thresholds.LoadCritical = *loadCritical
+		case "mem-warning":
+			thresholds.MemWarning = *memWarning
+		case "mem-critical":
+			thresholds.MemCritical = *memCritical
+		case "swap-warning":
+			thresholds.SwapWarning = *swapWarning
+		case "swap-critical":
+			thresholds.SwapCritical = *swapCritical
+		case "swap-interval":
+			cfg.Memory.SwapInterval = models.Duration(*swapInterval)
+		case "psi-cpu-warning":
+			thresholds.PSICPUWarning = *psiCPUWarning
+		case "psi-cpu-critical":
+			thresholds.PSICPUCritical = *psiCPUCritical
+		case "psi-memory-warning":
+			thresholds.PSIMemoryWarning = *psiMemoryWarning
+		case "psi-memory-critical":
+			thresholds.PSIMemoryCritical = *psiMemoryCritical
+		case "psi-io-warning":
+			thresholds.PSIIOWarning = *psiIOWarning
+		case "psi-io-critical":
+			thresholds.PSIIOCritical = *psiIOCritical
+		case "psi-memory-full-warning":
+			thresholds.PSIMemoryFullWarning = *psiMemoryFullWarning
+		case "psi-memory-full-critical":
+			thresholds.PSIMemoryFullCritical = *psiMemoryFullCritical
+		case "psi-io-full-warning":
+			thresholds.PSIIOFullWarning = *psiIOFullWarning
+		case "psi-io-full-critical":
+			thresholds.PSIIOFullCritical = *psiIOFullCritical
+		case "disk-warning":
+			thresholds.DiskWarning = *diskWarning
+		case "disk-critical":
+			thresholds.DiskCritical = *diskCritical
+		case "inode-warning":
+			thresholds.InodeWarning = *inodeWarning
+		case "inode-critical":
+			thresholds.InodeCritical = *inodeCritical
+		case "disk-util-warning":
+			thresholds.DiskUtilWarning = *diskUtilWarning
+		case "disk-util-critical":
+			thresholds.DiskUtilCritical = *diskUtilCritical
+		case "disk-await-warning":
+			thresholds.DiskAwaitWarning = *diskAwaitWarning
+		case "disk-await-critical":
+			thresholds.DiskAwaitCritical = *diskAwaitCritical
+		case "diskio-interval":
+			cfg.DiskIO.Interval = models.Duration(*diskIOInterval)
+		case "disk-warning-free":
+			thresholds.DiskWarningBytes = diskWarningBytes
+		case "disk-critical-free":
+			thresholds.DiskCriticalBytes = diskCriticalBytes
+		case "net-error-warning":
+			thresholds.NetErrorWarning = *netErrorWarning
+		case "net-error-critical":
+			thresholds.NetErrorCritical = *netErrorCritical
+		case "net-drop-warning":
+			thresholds.NetDropWarning = *netDropWarning
+		case "net-drop-critical":
+			thresholds.NetDropCritical = *netDropCritical
+		case "net-interval":
+			cfg.Network.Interval = models.Duration(*netInterval)
+		case "require-interfaces":
+			cfg.Network.RequiredInterfaces = splitList(*requiredInterfaces)
+		case "tcp-time-wait-warning":
+			thresholds.TCPTimeWaitWarning = *tcpTimeWaitWarning
+		case "tcp-time-wait-critical":
+			thresholds.TCPTimeWaitCritical = *tcpTimeWaitCritical
+		case "ephemeral-port-warning":
+			thresholds.EphemeralPortWarning = *ephemeralPortWarning
+		case "ephemeral-port-critical":
+			thresholds.EphemeralPortCritical = *ephemeralPortCritical
+		case "listen-overflow-warning":
+			thresholds.ListenOverflowWarning = *listenOverflowWarning
+		case "listen-overflow-critical":
+			thresholds.ListenOverflowCritical = *listenOverflowCritical
+		case "tcp-interval":
+			cfg.TCP.Interval = models.Duration(*tcpInterval)
+		case "process-interval":
+			cfg.Process.Interval = models.Duration(*processInterval)
+		case "process-state-file":
+			cfg.Process.StateFile = *processStateFile
+		case "restart-window":
+			cfg.Process.RestartWindow = models.Duration(*restartWindow)
+		case "restart-warning":
+			thresholds.RestartWarning = *restartWarning
+		case "restart-critical":
+			thresholds.RestartCritical = *restartCritical
+		case "listening":
+			cfg.Ports.Listening = splitList(*listening)
+		case "not-listening":
+			cfg.Ports.NotListening = splitList(*notListening)
+		case "file-handle-warning":
+			thresholds.FileHandleWarning = *fileHandleWarning
+		case "file-handle-critical":
+			thresholds.FileHandleCritical = *fileHandleCritical
+		case "pid-warning":
+			thresholds.PIDWarning = *pidWarning
+		case "pid-critical":
+			thresholds.PIDCritical = *pidCritical
+		case "conntrack-warning":
+			thresholds.ConntrackWarning = *conntrackWarning
+		case "conntrack-critical":
+			thresholds.ConntrackCritical = *conntrackCritical
+		case "temp-warning":
+			thresholds.TempWarning = *tempWarning
+		case "temp-critical":
+			thresholds.TempCritical = *tempCritical
+		case "require-mounts":
+			cfg.Disk.RequiredMounts = splitList(*requiredMounts)
+		case "writable-mounts":
+			cfg.Disk.WritableMounts = splitList(*writableMounts)
+		case "proc-root":
+			cfg.ProcRoot = *procRoot
+		case "sys-root":
+			cfg.SysRoot = *sysRoot
+		case "timeout":
+			cfg.Timeout = models.Duration(*timeout)
+		case "check-timeout":
+			cfg.CheckTimeouts = make(map[string]models.Duration, len(checkTimeouts))
+			for name, d := range checkTimeouts {
+				cfg.CheckTimeouts[name] = models.Duration(d)
+			}
+		}
+	})
+
+	// Normalize format
+	f := strings.ToLower(strings.TrimSpace(cfg.Format))
+
+	// Select the enabled checks (all built-ins unless configured)
+	enabled := cfg.Checks
+	if len(enabled) == 0 {
+		enabled = checker.BuiltinCheckNames()
 	}
-	if *memCritical >= 0 {
-		thresholds.MemCritical = *memCritical
+	// -process without the processes check would silently do nothing
+	if processSet && !slices.Contains(enabled, "processes") {
+		fmt.Fprintln(os.Stderr, "invalid options: -process requires the processes check, which is not enabled")
+		os.Exit(3)
 	}
-	if *diskWarning >= 0 {
-		thresholds.DiskWarning = *diskWarning
+	if err := cfg.Options.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "invalid options:", err)
+		os.Exit(3)
 	}
-	if *diskCritical >= 0 {
-		thresholds.DiskCritical = *diskCritical
+	registry, err := checker.NewRegistryFor(enabled, &cfg.Options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid checks:", err)
+		os.Exit(3)
 	}
 
-	// Normalize format
-	f := strings.ToLower(strings.TrimSpace(*format))
-
 	// Create health checker
-	hc, err := checker.NewHealthChecker(thresholds, f)
+	hc, err := checker.NewHealthCheckerWithRegistry(thresholds, f, registry)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "failed to create health checker:", err)
 		os.Exit(3)
 	}
-
-	// If process flag provided, try to check that process (don't fail hard)
-	if *processName != "" {
-		if err := hc.CheckProcess(*processName); err != nil {
-			// Print warning but continue
-			fmt.Fprintf(os.Stderr, "process check warning: %v\n", err)
+	if cfg.Timeout > 0 {
+		hc.SetTimeout(time.Duration(cfg.Timeout))
+	}
+	for name, d := range cfg.CheckTimeouts {
+		if err := hc.SetCheckTimeout(name, time.Duration(d)); err != nil {
+			fmt.Fprintln(os.Stderr, "invalid check timeouts:", err)
+			os.Exit(3)
 		}
 	}
 
-	// Run all checks
-	if err := hc.CheckAll(); err != nil {
+	// Stop outstanding checks on Ctrl-C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Run all checks; individual failures are reported as UNKNOWN results,
+	// so only abort when nothing at all could be collected
+	if err := hc.CheckAll(ctx); err != nil {
 		fmt.Fprintln(os.Stderr, "health checks failed:", err)
 		os.Exit(3)
 	}
@@ -76,19 +333,125 @@ func main() {
 	// Output according to selected format
 	switch f {
 	case "json":
-		output.PrintJSON(metrics, thresholds)
+		output.PrintJSON(metrics, overallStatus)
 	default:
 		// default to table
-		output.PrintTable(metrics, thresholds)
+		output.PrintTable(metrics, overallStatus)
 	}
 
 	// Exit code based on overall status
 	switch overallStatus {
-	case "CRITICAL":
+	case models.StatusCritical:
 		os.Exit(2)
-	case "WARNING":
+	case models.StatusWarning:
+		os.Exit(1)
+	case models.StatusUnknown:
+		// Some checks failed but the rest were collected and healthy
 		os.Exit(1)
 	default:
 		os.Exit(0)
 	}
 }
+
+// checkTimeoutFlag collects repeated -check-timeout name=duration flags
+type checkTimeoutFlag map[string]time.Duration
+
+func (f checkTimeoutFlag) String() string {
+	parts := make([]string, 0, len(f))
+	for name, d := range f {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, d))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Reset drops the values collected so far
+func (f checkTimeoutFlag) Reset() {
+	clear(f)
+}
+
+// Set accepts name=duration, or a comma-separated list of them
+func (f checkTimeoutFlag) Set(value string) error {
+	for _, item := range splitList(value) {
+		name, raw, ok := strings.Cut(item, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("expected name=duration, got %q", item)
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		f[name] = d
+	}
+	return nil
+}
+
+// processSpecFlag collects repeated -process specs
+type processSpecFlag []models.ProcessSpec
+
+func (f *processSpecFlag) String() string {
+	parts := make([]string, 0, len(*f))
+	for _, spec := range *f {
+		parts = append(parts, spec.Label())
+	}
+	return strings.Join(parts, ",")
+}
+
+// Reset drops the specs collected so far
+func (f *processSpecFlag) Reset() {
+	*f = nil
+}
+
+// Set accepts a process spec, or a comma-separated list of them. A spec
+// with ;-separated fields is taken whole, so cmdline patterns may
+// contain commas.
+func (f *processSpecFlag) Set(value string) error {
+	items := []string{value}
+	if !strings.Contains(value, ";") {
+		items = splitList(value)
+	}
+	for _, item := range items {
+		spec, err := models.ParseProcessSpec(item)
+		if err != nil {
+			return err
+		}
+		*f = append(*f, spec)
+	}
+	return nil
+}
+
+// resettableFlag is a repeatable flag whose collected values can be dropped
+type resettableFlag interface {
+	Reset()
+}
+
+// applyEnv sets every flag that has a matching HEALTHCHECKER_* environment
+// variable, so the environment takes precedence over the command line.
+// Repeatable flags are replaced, not appended to, like every other flag.
+func applyEnv() error {
+	var errs []error
+	flag.VisitAll(func(f *flag.Flag) {
+		name := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if r, ok := f.Value.(resettableFlag); ok {
+			r.Reset()
+		}
+		if err := flag.Set(f.Name, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1 +1,205 @@
 package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/checker"
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// staticCheck is a check that reports a fixed status
+type staticCheck struct {
+	name   string
+	status models.Status
+	delay  time.Duration
+	err    error
+}
+
+func (c *staticCheck) Name() string {
+	return c.name
+}
+
+// Collect blocks for delay, ignoring ctx like a hung syscall would
+func (c *staticCheck) Collect(ctx context.Context) error {
+	time.Sleep(c.delay)
+	return c.err
+}
+
+func (c *staticCheck) Evaluate(metrics *models.SystemMetrics, thresholds *models.Thresholds) *models.CheckResult {
+	result := models.NewCheckResult(c.name)
+	result.Status = c.status
+	result.AddRow(c.name, "static", c.status, "")
+	return result
+}
+
+func TestRegistryRejectsDuplicateNames(t *testing.T) {
+	r := checker.NewRegistry()
+	if err := r.Register(&staticCheck{name: "custom", status: models.StatusOK}); err != nil {
+		t.Fatalf("first register failed: %v", err)
+	}
+	if err := r.Register(&staticCheck{name: "custom", status: models.StatusOK}); err == nil {
+		t.Fatal("expected duplicate name to be rejected")
+	}
+	if got := len(r.Checks()); got != 1 {
+		t.Fatalf("expected 1 check, got %d", got)
+	}
+}
+
+func TestCustomCheckIsReported(t *testing.T) {
+	hc, err := checker.NewHealthChecker(nil, "json")
+	if err != nil {
+		t.Fatalf("NewHealthChecker failed: %v", err)
+	}
+	if err := hc.Register(&staticCheck{name: "custom", status: models.StatusCritical}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if err := hc.CheckAll(context.Background()); err != nil {
+		t.Fatalf("CheckAll failed: %v", err)
+	}
+
+	results := hc.GetMetrics().Results
+	last := results[len(results)-1]
+	if last.Name != "custom" {
+		t.Fatalf("expected custom check last, got %q", last.Name)
+	}
+	if got := hc.GetOverallStatus(); got != models.StatusCritical {
+		t.Fatalf("expected overall CRITICAL, got %s", got)
+	}
+}
+
+func TestHungCheckIsReportedUnknown(t *testing.T) {
+	hc, err := checker.NewHealthChecker(nil, "json")
+	if err != nil {
+		t.Fatalf("NewHealthChecker failed: %v", err)
+	}
+	if err := hc.Register(&staticCheck{name: "hung", status: models.StatusOK, delay: time.Hour}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if err := hc.SetCheckTimeout("hung", 50*time.Millisecond); err != nil {
+		t.Fatalf("SetCheckTimeout failed: %v", err)
+	}
+
+	start := time.Now()
+	if err := hc.CheckAll(context.Background()); err != nil {
+		t.Fatalf("CheckAll failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("CheckAll waited %s for a hung check", elapsed)
+	}
+
+	for _, r := range hc.GetMetrics().Results {
+		if r.Name == "hung" {
+			if r.Status != models.StatusUnknown {
+				t.Fatalf("expected UNKNOWN, got %s", r.Status)
+			}
+			return
+		}
+	}
+	t.Fatal("hung check missing from results")
+}
+
+func TestFailedCheckDoesNotAbortRun(t *testing.T) {
+	r := checker.NewRegistry()
+	r.MustRegister(&staticCheck{name: "broken", status: models.StatusOK, err: errors.New("boom")})
+	r.MustRegister(&staticCheck{name: "healthy", status: models.StatusWarning})
+	hc, err := checker.NewHealthCheckerWithRegistry(nil, "json", r)
+	if err != nil {
+		t.Fatalf("NewHealthCheckerWithRegistry failed: %v", err)
+	}
+
+	if err := hc.CheckAll(context.Background()); err != nil {
+		t.Fatalf("expected partial failure to succeed, got %v", err)
+	}
+	results := hc.GetMetrics().Results
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Status != models.StatusUnknown || results[0].Error != "boom" {
+		t.Fatalf("expected UNKNOWN with error, got %s %q", results[0].Status, results[0].Error)
+	}
+	if got := hc.GetOverallStatus(); got != models.StatusWarning {
+		t.Fatalf("expected overall WARNING, got %s", got)
+	}
+}
+
+func TestAllChecksFailedReturnsError(t *testing.T) {
+	r := checker.NewRegistry()
+	r.MustRegister(&staticCheck{name: "broken", status: models.StatusOK, err: errors.New("boom")})
+	hc, err := checker.NewHealthCheckerWithRegistry(nil, "json", r)
+	if err != nil {
+		t.Fatalf("NewHealthCheckerWithRegistry failed: %v", err)
+	}
+
+	if err := hc.CheckAll(context.Background()); err == nil {
+		t.Fatal("expected error when nothing could be collected")
+	}
+}
+
+func TestPortCheckDualStackViolatesIPv4NotListening(t *testing.T) {
+	l, err := net.Listen("tcp6", "[::]:0")
+	if err != nil {
+		t.Skipf("IPv6 not available: %v", err)
+	}
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	metrics, result := runCheck(t, &checker.PortCheck{Options: checker.PortOptions{
+		NotListening: []string{fmt.Sprintf("0.0.0.0:%d", port)},
+	}})
+	if len(metrics.Ports) != 1 || !metrics.Ports[0].Listening() {
+		t.Fatalf("expected the :: listener to violate the IPv4 rule, got %+v", metrics.Ports)
+	}
+	if result.Status != models.StatusCritical {
+		t.Errorf("expected CRITICAL, got %s", result.Status)
+	}
+}
+
+func TestSetCheckTimeoutRejectsUnknownNames(t *testing.T) {
+	registry, err := checker.NewRegistryFor([]string{"cpu"}, nil)
+	if err != nil {
+		t.Fatalf("NewRegistryFor failed: %v", err)
+	}
+	hc, err := checker.NewHealthCheckerWithRegistry(nil, "json", registry)
+	if err != nil {
+		t.Fatalf("NewHealthCheckerWithRegistry failed: %v", err)
+	}
+	if err := hc.SetCheckTimeout("dsks", 5*time.Second); err == nil || !strings.Contains(err.Error(), "dsks") {
+		t.Errorf("expected a typo to be rejected, got: %v", err)
+	}
+	// - A built-in check that is not enabled is still a valid name
+	if err := hc.SetCheckTimeout("disks", 5*time.Second); err != nil {
+		t.Errorf("expected a built-in name to be accepted, got: %v", err)
+	}
+	if err := hc.SetCheckTimeout("cpu", 5*time.Second); err != nil {
+		t.Errorf("expected a registered name to be accepted, got: %v", err)
+	}
+}
+
+func TestWindowedChecksStopOnCancel(t *testing.T) {
+	hour := models.Duration(time.Hour)
+	checks := []checker.Check{
+		&checker.CPUCheck{Options: checker.CPUOptions{Interval: hour}},
+		&checker.MemoryCheck{Options: checker.MemoryOptions{SwapInterval: hour}},
+		&checker.DiskIOCheck{Options: checker.DiskIOOptions{Interval: hour}},
+		&checker.NetworkCheck{Options: checker.NetworkOptions{Interval: hour}},
+		&checker.TCPCheck{ProcRoot: fixtureProcRoot, Options: checker.TCPOptions{Interval: hour}},
+	}
+	for _, c := range checks {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		err := c.Collect(ctx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s: expected the deadline error, got %v", c.Name(), err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("%s: waited %s after cancellation", c.Name(), elapsed)
+		}
+	}
+}
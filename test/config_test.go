@@ -0,0 +1,76 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/config"
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+func TestLoadConfigFormats(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.toml", "config.json"} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := config.Load(filepath.Join("testdata", name))
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if cfg.Format != "json" {
+				t.Errorf("expected format json, got %q", cfg.Format)
+			}
+			if len(cfg.Checks) != 2 || cfg.Checks[0] != "cpu" || cfg.Checks[1] != "disks" {
+				t.Errorf("unexpected checks %v", cfg.Checks)
+			}
+			if time.Duration(cfg.Timeout) != 5*time.Second {
+				t.Errorf("expected timeout 5s, got %s", time.Duration(cfg.Timeout))
+			}
+			if time.Duration(cfg.CheckTimeouts["disks"]) != 30*time.Second {
+				t.Errorf("expected disks timeout 30s, got %s", time.Duration(cfg.CheckTimeouts["disks"]))
+			}
+			if cfg.Thresholds.CPUWarning != 70 || cfg.Thresholds.DiskCritical != 5 {
+				t.Errorf("file thresholds not applied: %+v", cfg.Thresholds)
+			}
+			// Keys missing from the file keep their defaults
+			if cfg.Thresholds.CPUCritical != 90 || cfg.Thresholds.DiskWarning != 20 {
+				t.Errorf("defaults not preserved: %+v", cfg.Thresholds)
+			}
+		})
+	}
+}
+
+func TestLoadConfigRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(path, []byte("thresholds:\n  cpu_warnin: 70\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Load(path); err == nil {
+		t.Fatal("expected unknown key to be rejected")
+	}
+}
+
+func TestLoadConfigProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "processes.yaml")
+	data := "processes:\n  - nginx\n  - worker=2:4\n  - name: cron\n    max: 1\n    rss_warning: 512MiB\n    fds_critical: 100\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := []models.ProcessSpec{
+		{Name: "nginx", Min: 1},
+		{Name: "worker", Min: 2, Max: 4},
+		{Name: "cron", Min: 1, Max: 1, ProcessLimits: models.ProcessLimits{RSSWarning: 512 << 20, FDsCritical: 100}},
+	}
+	if len(cfg.Processes) != len(want) {
+		t.Fatalf("expected %d processes, got %+v", len(want), cfg.Processes)
+	}
+	for i, spec := range want {
+		if cfg.Processes[i] != spec {
+			t.Errorf("process %d: expected %+v, got %+v", i, spec, cfg.Processes[i])
+		}
+	}
+}
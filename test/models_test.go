@@ -0,0 +1,533 @@
+package test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+func TestStatusOrdering(t *testing.T) {
+	if got := models.StatusOK.Worse(models.StatusUnknown); got != models.StatusUnknown {
+		t.Fatalf("expected UNKNOWN to outrank OK, got %s", got)
+	}
+	if got := models.StatusUnknown.Worse(models.StatusWarning); got != models.StatusWarning {
+		t.Fatalf("expected WARNING to outrank UNKNOWN, got %s", got)
+	}
+	if got := models.StatusCritical.Worse(models.StatusWarning); got != models.StatusCritical {
+		t.Fatalf("expected CRITICAL to outrank WARNING, got %s", got)
+	}
+}
+
+func TestStatusJSONRoundTrip(t *testing.T) {
+	out, err := json.Marshal(models.StatusWarning)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(out) != `"WARNING"` {
+		t.Fatalf("expected \"WARNING\", got %s", out)
+	}
+
+	var s models.Status
+	if err := json.Unmarshal([]byte(`"CRITICAL"`), &s); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if s != models.StatusCritical {
+		t.Fatalf("expected CRITICAL, got %s", s)
+	}
+	if err := json.Unmarshal([]byte(`"BROKEN"`), &s); err == nil {
+		t.Fatal("expected invalid status to be rejected")
+	}
+}
+
+func TestDiskGetStatus(t *testing.T) {
+	thresholds := models.NewDefaultThresholds()
+	cases := []struct {
+		used uint64
+		want models.Status
+	}{
+		{used: 50, want: models.StatusOK},
+		{used: 85, want: models.StatusWarning},
+		{used: 95, want: models.StatusCritical},
+	}
+	for _, c := range cases {
+		d := models.NewDiskInfo("/", c.used, 100)
+		if got := d.GetStatus(thresholds); got != c.want {
+			t.Errorf("used %d%%: expected %s, got %s", c.used, c.want, got)
+		}
+	}
+}
+
+func TestThresholdsValidate(t *testing.T) {
+	if err := models.NewDefaultThresholds().Validate(); err != nil {
+		t.Fatalf("defaults should be valid: %v", err)
+	}
+
+	th := models.NewDefaultThresholds()
+	th.CPUWarning = 95
+	th.CPUCritical = 50
+	th.MemCritical = math.NaN()
+	th.DiskWarning = 5
+	th.DiskCritical = 10
+	err := th.Validate()
+	if err == nil {
+		t.Fatal("expected invalid thresholds to be rejected")
+	}
+	for _, want := range []string{"cpu-warning", "mem-critical", "disk-warning"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %s, got: %v", want, err)
+		}
+	}
+
+	th = models.NewDefaultThresholds()
+	th.DiskCritical = 101
+	if err := th.Validate(); err == nil {
+		t.Fatal("expected out-of-range disk threshold to be rejected")
+	}
+
+	// Load thresholds are per CPU and have no upper bound
+	th = models.NewDefaultThresholds()
+	th.LoadWarning = 150
+	th.LoadCritical = 200
+	if err := th.Validate(); err != nil {
+		t.Errorf("expected large load thresholds to be valid: %v", err)
+	}
+	th.LoadWarning = -1
+	if err := th.Validate(); err == nil {
+		t.Error("expected negative load threshold to be rejected")
+	}
+
+	th = models.NewDefaultThresholds()
+	th.RestartWarning = 10
+	th.RestartCritical = 3
+	if err := th.Validate(); err == nil || !strings.Contains(err.Error(), "restart-warning") {
+		t.Errorf("expected restart warning above critical to be rejected, got: %v", err)
+	}
+}
+
+func TestThresholdsForDisk(t *testing.T) {
+	th := models.NewDefaultThresholds()
+	th.DiskMounts = []models.DiskMountThreshold{
+		{Mount: "/data/*", DiskLimits: models.DiskLimits{Warning: 30, Critical: 15}},
+		{Mount: "/data/db", DiskLimits: models.DiskLimits{Warning: 40, Critical: 25}},
+	}
+
+	cases := []struct {
+		mount             string
+		warning, critical float64
+	}{
+		{mount: "/data/db", warning: 40, critical: 25},
+		{mount: "/data/logs", warning: 30, critical: 15},
+		{mount: "/boot", warning: 20, critical: 10},
+	}
+	for _, c := range cases {
+		limits := th.ForDisk(c.mount)
+		if limits.Warning != c.warning || limits.Critical != c.critical {
+			t.Errorf("%s: expected %v/%v, got %v/%v", c.mount, c.warning, c.critical, limits.Warning, limits.Critical)
+		}
+	}
+
+	d := models.NewDiskInfo("/data/db", 65, 100)
+	if got := d.GetStatus(th); got != models.StatusWarning {
+		t.Errorf("expected per-mount WARNING, got %s", got)
+	}
+}
+
+func TestThresholdsForDiskInheritsByteLimits(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+	th := models.NewDefaultThresholds()
+	th.DiskWarningBytes = 5 * gib
+	th.DiskCriticalBytes = 2 * gib
+	th.DiskMounts = []models.DiskMountThreshold{
+		{Mount: "/boot", DiskLimits: models.DiskLimits{Warning: 30, Critical: 15, CriticalBytes: 1 * gib}},
+		{Mount: "/data/*", DiskLimits: models.DiskLimits{Warning: 40, Critical: 25}},
+	}
+
+	// A percent-only override keeps both global byte limits
+	if limits := th.ForDisk("/data/logs"); limits.WarningBytes != 5*gib || limits.CriticalBytes != 2*gib {
+		t.Errorf("/data/logs: expected inherited 5GiB/2GiB, got %s/%s", limits.WarningBytes, limits.CriticalBytes)
+	}
+	// An override replaces only the byte limits it sets
+	if limits := th.ForDisk("/boot"); limits.WarningBytes != 5*gib || limits.CriticalBytes != 1*gib {
+		t.Errorf("/boot: expected 5GiB/1GiB, got %s/%s", limits.WarningBytes, limits.CriticalBytes)
+	}
+
+	// 4 GiB free of 8 GiB on /data/logs: 50% free passes the override's
+	// percent rule, but the inherited 5GiB warning still fires
+	d := models.NewDiskInfo("/data/logs", 4*gib, 8*gib)
+	if status, rule := d.Evaluate(th); status != models.StatusWarning || rule != "< 5.0GiB free" {
+		t.Errorf("expected WARNING by inherited bytes rule, got %s (%s)", status, rule)
+	}
+
+	// An override warning below the inherited global critical is inverted
+	th.DiskMounts = []models.DiskMountThreshold{
+		{Mount: "/tmp", DiskLimits: models.DiskLimits{Warning: 30, Critical: 15, WarningBytes: 1 * gib}},
+	}
+	if err := th.Validate(); err == nil || !strings.Contains(err.Error(), "disk[/tmp]-warning-bytes") {
+		t.Errorf("expected inherited byte limits to be validated, got: %v", err)
+	}
+}
+
+func TestDiskAbsoluteFreeThreshold(t *testing.T) {
+	const gib = 1 << 30
+	th := models.NewDefaultThresholds()
+	th.DiskWarningBytes = 5 * gib
+	th.DiskCriticalBytes = 2 * gib
+
+	// 10 TiB array with 3 TiB free: fine by both rules
+	big := models.NewDiskInfo("/data", 7*1024*gib, 10*1024*gib)
+	if status, rule := big.Evaluate(th); status != models.StatusOK || rule != "" {
+		t.Errorf("big disk: expected OK, got %s (%s)", status, rule)
+	}
+
+	// 4 GiB free of 10 GiB: 40% free is fine, but below the 5GiB warning
+	small := models.NewDiskInfo("/boot", 6*gib, 10*gib)
+	if status, rule := small.Evaluate(th); status != models.StatusWarning || rule != "< 5.0GiB free" {
+		t.Errorf("small disk: expected WARNING by bytes, got %s (%s)", status, rule)
+	}
+
+	// 8% free trips the percent critical rule before the bytes rule
+	full := models.NewDiskInfo("/", 92*gib, 100*gib)
+	if status, rule := full.Evaluate(th); status != models.StatusCritical || rule != "< 10% free" {
+		t.Errorf("full disk: expected CRITICAL by percent, got %s (%s)", status, rule)
+	}
+
+	// 6 GiB unused but 5% of 100 GiB is reserved for root: only 1 GiB is
+	// available to services, so the bytes rule must see 1 GiB
+	reserved := models.NewDiskInfo("/srv", 80*gib, 100*gib)
+	reserved.FreeBytes = 1 * gib
+	if status, rule := reserved.Evaluate(th); status != models.StatusCritical || rule != "< 2.0GiB free" {
+		t.Errorf("reserved blocks: expected CRITICAL by bytes, got %s (%s)", status, rule)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]models.ByteSize{
+		"1024":   1024,
+		"2GiB":   2 << 30,
+		"2G":     2 << 30,
+		"500MB":  500 * 1000 * 1000,
+		"1.5KiB": 1536,
+	}
+	for text, want := range cases {
+		got, err := models.ParseByteSize(text)
+		if err != nil || got != want {
+			t.Errorf("%s: expected %d, got %d (%v)", text, want, got, err)
+		}
+	}
+	if _, err := models.ParseByteSize("2XB"); err == nil {
+		t.Error("expected unknown unit to be rejected")
+	}
+}
+
+func TestDiskInodeStatus(t *testing.T) {
+	th := models.NewDefaultThresholds()
+
+	d := models.NewDiskInfo("/var/spool", 10, 100)
+	d.InodesUsed = 95
+	d.InodesTotal = 100
+	if got := d.GetInodeStatus(th); got != models.StatusCritical {
+		t.Errorf("expected inode CRITICAL with plenty of bytes free, got %s", got)
+	}
+
+	// Filesystems without fixed inodes report zero totals
+	d.InodesUsed, d.InodesTotal = 0, 0
+	if got := d.GetInodeStatus(th); got != models.StatusOK {
+		t.Errorf("expected OK without inode data, got %s", got)
+	}
+}
+
+func TestMountStatus(t *testing.T) {
+	missing := models.NewMountInfo("/mnt/nfs")
+	missing.Required = true
+	if got := missing.GetStatus(false); got != models.StatusCritical {
+		t.Errorf("missing required mount: expected CRITICAL, got %s", got)
+	}
+
+	ro := models.NewMountInfo("/data")
+	ro.SetPartition("/dev/sdb1", "ext4", []string{"ro", "relatime"})
+	if got := ro.GetStatus(true); got != models.StatusCritical {
+		t.Errorf("read-only writable mount: expected CRITICAL, got %s", got)
+	}
+	if got := ro.GetStatus(false); got != models.StatusOK {
+		t.Errorf("read-only mount not expected writable: expected OK, got %s", got)
+	}
+
+	rw := models.NewMountInfo("/data")
+	rw.SetPartition("/dev/sdb1", "ext4", []string{"rw", "errors=remount-ro"})
+	if got := rw.GetStatus(true); got != models.StatusOK {
+		t.Errorf("read-write mount: expected OK, got %s", got)
+	}
+}
+
+func TestCPUCoreCriticalDisabledByDefault(t *testing.T) {
+	th := models.NewDefaultThresholds()
+	if th.CPUCoreCritical != 0 {
+		t.Errorf("expected the hot-core rule to be WARNING-only by default, got critical %v", th.CPUCoreCritical)
+	}
+	if err := th.Validate(); err != nil {
+		t.Errorf("expected defaults to validate, got: %v", err)
+	}
+
+	th.CPUCoreCritical = 90
+	if err := th.Validate(); err == nil || !strings.Contains(err.Error(), "cpu-core-warning") {
+		t.Errorf("expected core warning above critical to be rejected, got: %v", err)
+	}
+}
+
+func TestTemperatureLimitsWarningCapped(t *testing.T) {
+	cases := []struct {
+		name              string
+		sensor            models.TemperatureInfo
+		tempCritical      float64
+		warning, critical float64
+	}{
+		// -temp-critical=90 with a sensor max of 95
+		{name: "configured critical below sensor max", sensor: models.TemperatureInfo{Max: 95, Crit: 105}, tempCritical: 90, warning: 90, critical: 90},
+		// Sensor crit below DefaultTempWarning and no max
+		{name: "sensor crit below default warning", sensor: models.TemperatureInfo{Crit: 70}, warning: 70, critical: 70},
+		{name: "consistent sensor limits", sensor: models.TemperatureInfo{Max: 84, Crit: 100}, warning: 84, critical: 100},
+	}
+	for _, c := range cases {
+		th := models.NewDefaultThresholds()
+		th.TempCritical = c.tempCritical
+		warning, critical := c.sensor.Limits(th)
+		if warning != c.warning || critical != c.critical {
+			t.Errorf("%s: expected %v/%v, got %v/%v", c.name, c.warning, c.critical, warning, critical)
+		}
+	}
+}
+
+func TestGetHottestCore(t *testing.T) {
+	sm := models.NewSystemMetrics()
+	if index, _ := sm.GetHottestCore(); index != -1 {
+		t.Errorf("expected -1 without per-core data, got %d", index)
+	}
+
+	sm.CPUCores = []float64{12.5, 97.0, 40.0}
+	index, percent := sm.GetHottestCore()
+	if index != 1 || percent != 97.0 {
+		t.Errorf("expected cpu1 at 97%%, got cpu%d at %v%%", index, percent)
+	}
+}
+
+func TestLoadPerCPU(t *testing.T) {
+	li := &models.LoadInfo{Load1: 6, CPUs: 4}
+	if got := li.PerCPU(li.Load1); got != 1.5 {
+		t.Errorf("expected 1.5 per CPU, got %v", got)
+	}
+
+	li.CPUs = 0
+	if got := li.PerCPU(li.Load1); got != 6 {
+		t.Errorf("expected raw load without a CPU count, got %v", got)
+	}
+}
+
+func TestMemoryPercentUsesAvailable(t *testing.T) {
+	// - 90% "used" that is mostly page cache is not memory pressure
+	sm := models.NewSystemMetrics()
+	sm.MemoryTotal = 100
+	sm.MemoryUsed = 90
+	sm.MemoryCached = 70
+	sm.MemoryAvailable = 75
+	if got := sm.GetMemoryPercent(); got != 25 {
+		t.Errorf("expected 25%% in use, got %v", got)
+	}
+
+	if got := sm.GetSwapPercent(); got != 0 {
+		t.Errorf("expected 0%% without swap, got %v", got)
+	}
+	sm.SwapTotal = 200
+	sm.SwapUsed = 50
+	if got := sm.GetSwapPercent(); got != 25 {
+		t.Errorf("expected 25%% swap used, got %v", got)
+	}
+}
+
+func TestDiskIOStatus(t *testing.T) {
+	th := models.NewDefaultThresholds()
+	cases := []struct {
+		util, await float64
+		want        models.Status
+	}{
+		{util: 10, await: 2, want: models.StatusOK},
+		{util: 85, await: 2, want: models.StatusWarning},
+		{util: 10, await: 250, want: models.StatusCritical},
+		{util: 96, await: 60, want: models.StatusCritical},
+	}
+	for _, c := range cases {
+		d := &models.DiskIOInfo{Device: "sda", UtilPercent: c.util, AwaitMs: c.await}
+		if got := d.GetStatus(th); got != c.want {
+			t.Errorf("util %v%% await %vms: expected %s, got %s", c.util, c.await, c.want, got)
+		}
+	}
+}
+
+func TestNetInterfaceStatus(t *testing.T) {
+	th := models.NewDefaultThresholds()
+	cases := []struct {
+		name  string
+		iface models.NetInterfaceInfo
+		want  models.Status
+	}{
+		{name: "healthy", iface: models.NetInterfaceInfo{Present: true, Up: true}, want: models.StatusOK},
+		{name: "optional down", iface: models.NetInterfaceInfo{Present: true}, want: models.StatusOK},
+		{name: "required down", iface: models.NetInterfaceInfo{Present: true, Required: true}, want: models.StatusCritical},
+		{name: "required missing", iface: models.NetInterfaceInfo{Required: true}, want: models.StatusCritical},
+		{name: "errors", iface: models.NetInterfaceInfo{Present: true, Up: true, ErrorsPerSec: 2}, want: models.StatusWarning},
+		{name: "drops", iface: models.NetInterfaceInfo{Present: true, Up: true, DropsPerSec: 150}, want: models.StatusCritical},
+	}
+	for _, c := range cases {
+		if got := c.iface.GetStatus(th); got != c.want {
+			t.Errorf("%s: expected %s, got %s", c.name, c.want, got)
+		}
+	}
+}
+
+func TestListenAddress(t *testing.T) {
+	for _, bad := range []string{"", "0", "70000", "db:5432", "127.0.0.1:"} {
+		if _, err := models.ParseListenAddress(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+
+	local, err := models.ParseListenAddress("127.0.0.1:5432")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	anyAddr, err := models.ParseListenAddress("5432")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	cases := []struct {
+		name            string
+		address         models.ListenAddress
+		host            string
+		served, matches bool
+	}{
+		{name: "exact", address: local, host: "127.0.0.1", served: true, matches: true},
+		{name: "ipv4 wildcard", address: local, host: "0.0.0.0", served: true, matches: false},
+		{name: "ipv6 wildcard", address: local, host: "::", served: true, matches: false},
+		{name: "other address", address: local, host: "10.0.0.5", served: false, matches: false},
+		{name: "any address", address: anyAddr, host: "10.0.0.5", served: true, matches: true},
+	}
+	for _, c := range cases {
+		if got := c.address.ServedBy(c.host, 5432); got != c.served {
+			t.Errorf("%s: ServedBy expected %v, got %v", c.name, c.served, got)
+		}
+		if got := c.address.Matches(c.host, 5432); got != c.matches {
+			t.Errorf("%s: Matches expected %v, got %v", c.name, c.matches, got)
+		}
+	}
+
+	// A dual-stack "::" listener also serves the IPv4 wildcard, but an
+	// IPv4 listener does not serve the IPv6 wildcard
+	ipv4Any, _ := models.ParseListenAddress("0.0.0.0:5432")
+	ipv6Any, _ := models.ParseListenAddress("[::]:5432")
+	if !ipv4Any.ServedBy("::", 5432) {
+		t.Errorf("expected a :: listener to serve 0.0.0.0")
+	}
+	if ipv6Any.ServedBy("0.0.0.0", 5432) {
+		t.Errorf("expected a 0.0.0.0 listener not to serve ::")
+	}
+
+	forbidden := &models.PortAssertion{Address: anyAddr, Expect: false, Listeners: []models.PortListener{{Host: "0.0.0.0", Port: 5432}}}
+	if got := forbidden.GetStatus(); got != models.StatusCritical {
+		t.Errorf("forbidden listener: expected CRITICAL, got %s", got)
+	}
+}
+
+func TestParseProcessSpec(t *testing.T) {
+	cases := []struct {
+		text     string
+		min, max int
+	}{
+		{text: "nginx", min: 1, max: 0},
+		{text: "worker=3", min: 3, max: 3},
+		{text: "worker=2:4", min: 2, max: 4},
+		{text: "worker=2:", min: 2, max: 0},
+		{text: "worker=:4", min: 0, max: 4},
+	}
+	for _, c := range cases {
+		spec, err := models.ParseProcessSpec(c.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.text, err)
+			continue
+		}
+		if spec.Min != c.min || spec.Max != c.max {
+			t.Errorf("%q: expected %d-%d, got %d-%d", c.text, c.min, c.max, spec.Min, spec.Max)
+		}
+	}
+
+	spec, err := models.ParseProcessSpec("java; cmdline=-jar app,v2\\.jar; user=app; parent=systemd; pidfile=/run/app.pid; count=2:")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := models.ProcessSpec{Name: "java", Cmdline: "-jar app,v2\\.jar", User: "app", Parent: "systemd", Pidfile: "/run/app.pid", Min: 2}
+	if spec != want {
+		t.Errorf("expected %+v, got %+v", want, spec)
+	}
+	spec, err = models.ParseProcessSpec("java;cpu=80:95;rss=:2GiB;fds=1000;threads=200:400;io=10MiB:50MiB")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	limits := models.ProcessLimits{CPUWarning: 80, CPUCritical: 95, RSSCritical: 2 << 30, FDsWarning: 1000, ThreadsWarning: 200, ThreadsCritical: 400, IOWarning: 10 << 20, IOCritical: 50 << 20}
+	if spec.ProcessLimits != limits {
+		t.Errorf("expected limits %+v, got %+v", limits, spec.ProcessLimits)
+	}
+	if spec, err := models.ParseProcessSpec("cmdline=kafka\\.Kafka"); err != nil || spec.Name != "" || spec.Min != 1 {
+		t.Errorf("cmdline-only spec: got %+v, %v", spec, err)
+	}
+
+	for _, bad := range []string{"", "=2", "worker=x", "worker=-1", "worker=4:2", "worker=0", "count=1", "cmdline=(", "java;color=red", "java;cpu=90:80", "java;rss=lots", "java;fds=-1"} {
+		if _, err := models.ParseProcessSpec(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestProcessSpecStatus(t *testing.T) {
+	spec := models.ProcessSpec{Name: "worker", Min: 2, Max: 4}
+	cases := []struct {
+		count int
+		want  models.Status
+	}{
+		{count: 0, want: models.StatusCritical},
+		{count: 1, want: models.StatusCritical},
+		{count: 2, want: models.StatusOK},
+		{count: 4, want: models.StatusOK},
+		{count: 5, want: models.StatusWarning},
+	}
+	for _, c := range cases {
+		if got := spec.GetStatus(c.count); got != c.want {
+			t.Errorf("count %d: expected %s, got %s", c.count, c.want, got)
+		}
+	}
+}
+
+func TestProcessLimits(t *testing.T) {
+	limits := models.ProcessLimits{CPUWarning: 80, CPUCritical: 95, RSSCritical: 1 << 30, FDsWarning: 1000, IOWarning: 10 << 20}
+	cases := []struct {
+		name string
+		info models.ProcessInfo
+		want models.Status
+	}{
+		{name: "idle", info: models.ProcessInfo{CPUPercent: 5, RSSBytes: 1 << 20, NumFDs: 10, NumThreads: 5000}, want: models.StatusOK},
+		{name: "busy", info: models.ProcessInfo{CPUPercent: 85}, want: models.StatusWarning},
+		{name: "pegged", info: models.ProcessInfo{CPUPercent: 150}, want: models.StatusCritical},
+		{name: "rss", info: models.ProcessInfo{RSSBytes: 2 << 30}, want: models.StatusCritical},
+		{name: "fds", info: models.ProcessInfo{NumFDs: 1000}, want: models.StatusWarning},
+		{name: "io", info: models.ProcessInfo{ReadBytesPerSec: 6 << 20, WriteBytesPerSec: 6 << 20}, want: models.StatusWarning},
+	}
+	for _, c := range cases {
+		if got := limits.Evaluate(&c.info); got != c.want {
+			t.Errorf("%s: expected %s, got %s", c.name, c.want, got)
+		}
+	}
+	if got := (models.ProcessLimits{}).Evaluate(&models.ProcessInfo{CPUPercent: 400}); got != models.StatusOK {
+		t.Errorf("no limits: expected OK, got %s", got)
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/checker"
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+func TestDiskOptionsSelects(t *testing.T) {
+	opts := checker.DiskOptions{
+		IncludeMounts:  []string{"/", "/data*"},
+		ExcludeMounts:  []string{"/data/tmp"},
+		ExcludeFSTypes: []string{"squashfs"},
+		ExcludeDevices: []string{"/dev/loop*"},
+	}
+
+	cases := []struct {
+		mount, fstype, device string
+		want                  bool
+	}{
+		{mount: "/", fstype: "ext4", device: "/dev/sda1", want: true},
+		{mount: "/data", fstype: "xfs", device: "/dev/sdb1", want: true},
+		{mount: "/data/tmp", fstype: "xfs", device: "/dev/sdb2", want: false},
+		{mount: "/boot", fstype: "ext4", device: "/dev/sda2", want: false},
+		{mount: "/data/snap", fstype: "squashfs", device: "/dev/sdc1", want: false},
+		{mount: "/data/img", fstype: "ext4", device: "/dev/loop3", want: false},
+	}
+	for _, c := range cases {
+		if got := opts.Selects(c.mount, c.fstype, c.device); got != c.want {
+			t.Errorf("%s (%s): expected %v, got %v", c.mount, c.fstype, c.want, got)
+		}
+	}
+}
+
+func TestDiskOptionsRecursivePattern(t *testing.T) {
+	opts := checker.DiskOptions{ExcludeMounts: []string{"/snap/**"}}
+
+	if opts.Selects("/snap", "squashfs", "/dev/loop0") {
+		t.Error("expected /snap itself to be excluded")
+	}
+	if opts.Selects("/snap/core20/1234", "squashfs", "/dev/loop1") {
+		t.Error("expected nested snap mount to be excluded")
+	}
+	if !opts.Selects("/snapshots", "ext4", "/dev/sdb1") {
+		t.Error("expected /snapshots to stay included")
+	}
+}
+
+func TestProcessOptionsRestartWindow(t *testing.T) {
+	opts := checker.NewDefaultOptions()
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("defaults should be valid: %v", err)
+	}
+
+	// - Without a state file the window is unused
+	opts.Process.RestartWindow = 0
+	if err := opts.Validate(); err != nil {
+		t.Errorf("zero window without state file: unexpected error: %v", err)
+	}
+
+	// - With a state file a zero or negative window would drop every restart
+	opts.Process.StateFile = "/var/lib/healthchecker/processes.json"
+	for _, window := range []models.Duration{0, models.Duration(-time.Minute)} {
+		opts.Process.RestartWindow = window
+		if err := opts.Validate(); err == nil {
+			t.Errorf("window %s: expected an error", window)
+		}
+	}
+}
+
+func TestProcessSpecsMustBeDistinct(t *testing.T) {
+	parse := func(spec string) models.ProcessSpec {
+		t.Helper()
+		s, err := models.ParseProcessSpec(spec)
+		if err != nil {
+			t.Fatalf("parse %q: %v", spec, err)
+		}
+		return s
+	}
+
+	// - Different counts or limits do not make the same processes distinct
+	opts := checker.NewDefaultOptions()
+	opts.Processes = []models.ProcessSpec{parse("nginx=2"), parse("nginx;user=www"), parse("nginx=1:4")}
+	if err := opts.Validate(); err == nil || !strings.Contains(err.Error(), "nginx is listed more than once") {
+		t.Errorf("expected duplicate spec to be rejected, got: %v", err)
+	}
+
+	// - Labels can collide where the match criteria differ; keys do not
+	a := models.ProcessSpec{Cmdline: "java user=app", Min: 1}
+	b := models.ProcessSpec{Cmdline: "java", User: "app", Min: 1}
+	if a.Label() != b.Label() || a.Key() == b.Key() {
+		t.Fatalf("expected equal labels and distinct keys, got %q/%q and %q/%q", a.Label(), b.Label(), a.Key(), b.Key())
+	}
+	opts.Processes = []models.ProcessSpec{a, b}
+	if err := opts.Validate(); err != nil {
+		t.Errorf("distinct specs: unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/checker"
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// writePidfile writes pid to a pidfile in a temporary directory
+func writePidfile(t *testing.T, pid int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.pid")
+	if err := os.WriteFile(path, []byte(fmt.Sprintf("%d\n", pid)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProcessCheckMatchers(t *testing.T) {
+	self := int32(os.Getpid())
+	binary := regexp.QuoteMeta(filepath.Base(os.Args[0]))
+	c := &checker.ProcessCheck{Specs: []models.ProcessSpec{
+		{Pidfile: writePidfile(t, os.Getpid()), Min: 1},
+		{Cmdline: binary, Pidfile: writePidfile(t, os.Getpid()), Min: 1, Max: 1},
+		{Cmdline: "^no-such-process-", Min: 1},
+		{Parent: fmt.Sprint(os.Getppid()), Cmdline: binary, Min: 1},
+	}}
+	metrics, result := runCheck(t, c)
+
+	specs := result.Data.([]checker.ProcessResult)
+	for i, want := range []int{1, 1, 0, 1} {
+		if specs[i].Count != want {
+			t.Errorf("spec %s: expected %d instances, got %d", specs[i].Name, want, specs[i].Count)
+		}
+	}
+	if specs[0].Count == 1 && specs[0].Instances[0].PID != self {
+		t.Errorf("pidfile matched pid %d, expected %d", specs[0].Instances[0].PID, self)
+	}
+	if specs[2].Status != models.StatusCritical || result.Status != models.StatusCritical {
+		t.Errorf("missing process: expected CRITICAL, got %s (overall %s)", specs[2].Status, result.Status)
+	}
+	// The test process matches three specs but is recorded once
+	if len(metrics.Processes) != 1 {
+		t.Errorf("expected 1 recorded process, got %d", len(metrics.Processes))
+	}
+}
+
+func TestProcessCheckStalePidfile(t *testing.T) {
+	c := &checker.ProcessCheck{Specs: []models.ProcessSpec{
+		{Pidfile: writePidfile(t, 1<<22+1), Min: 1},
+		{Pidfile: filepath.Join(t.TempDir(), "missing.pid"), Min: 1},
+	}}
+	_, result := runCheck(t, c)
+
+	for _, spec := range result.Data.([]checker.ProcessResult) {
+		if spec.Count != 0 || spec.Status != models.StatusCritical || spec.Error == "" {
+			t.Errorf("%s: expected CRITICAL with an error, got %d instances, %s, %q", spec.Name, spec.Count, spec.Status, spec.Error)
+		}
+	}
+}
+
+func TestProcessCheckSamplesCPU(t *testing.T) {
+	c := &checker.ProcessCheck{
+		Specs:   []models.ProcessSpec{{Pidfile: writePidfile(t, os.Getpid()), Min: 1}},
+		Options: checker.ProcessOptions{Interval: models.Duration(300 * time.Millisecond)},
+	}
+
+	// Keep one CPU busy for the whole sampling window
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+		}
+	}()
+	metrics, _ := runCheck(t, c)
+
+	if len(metrics.Processes) != 1 {
+		t.Fatalf("expected 1 recorded process, got %d", len(metrics.Processes))
+	}
+	p := metrics.Processes[0]
+	if p.PID != int32(os.Getpid()) {
+		t.Errorf("expected pid %d, got %d", os.Getpid(), p.PID)
+	}
+	if p.CPUPercent < 20 {
+		t.Errorf("expected the busy loop to show in the sampled CPU, got %.1f%%", p.CPUPercent)
+	}
+	if p.StartTime.IsZero() || p.StartTime.After(time.Now()) || p.Uptime <= 0 {
+		t.Errorf("unexpected start time %s and uptime %s", p.StartTime, p.Uptime)
+	}
+}
+
+func TestProcessCheckRestarts(t *testing.T) {
+	pidfile := writePidfile(t, os.Getpid())
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	spec := models.ProcessSpec{Pidfile: pidfile, Min: 1}
+
+	// One restart inside the window and one outside it; the instance seen
+	// at the last run is gone and the test process replaced it, so its
+	// start is a restart too
+	now := time.Now().UTC()
+	state := fmt.Sprintf(`{"specs": {%q: {"last_run": %q, "instances": [{"pid": %d, "start_time": %q}], "restarts": [%q, %q]}}}`,
+		spec.Key(),
+		time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339),
+		1<<22+1,
+		time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339),
+		now.Add(-2*time.Hour).Format(time.RFC3339),
+		now.Add(-10*time.Minute).Format(time.RFC3339))
+	if err := os.WriteFile(stateFile, []byte(state), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &checker.ProcessCheck{
+		Specs:   []models.ProcessSpec{spec},
+		Options: checker.ProcessOptions{StateFile: stateFile, RestartWindow: models.Duration(time.Hour)},
+	}
+	for run := 1; run <= 2; run++ {
+		_, result := runCheck(t, c)
+		restarts := result.Data.([]checker.ProcessResult)[0].Restarts
+		if restarts == nil {
+			t.Fatalf("run %d: restarts not reported", run)
+		}
+		// The test process is only counted once, at the first run
+		if restarts.Count != 2 || restarts.Status != models.StatusWarning {
+			t.Errorf("run %d: expected 2 restarts and WARNING, got %d and %s", run, restarts.Count, restarts.Status)
+		}
+	}
+}
+
+// startSleep starts a sleep process that is killed when the test ends
+func startSleep(t *testing.T, arg string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", arg)
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return cmd
+}
+
+func TestProcessCheckScaleUpIsNotRestart(t *testing.T) {
+	arg := fmt.Sprintf("1000.%d", os.Getpid())
+	spec := models.ProcessSpec{Cmdline: "^sleep " + regexp.QuoteMeta(arg) + "$", Min: 1}
+	c := &checker.ProcessCheck{
+		Specs: []models.ProcessSpec{spec},
+		Options: checker.ProcessOptions{
+			StateFile:     filepath.Join(t.TempDir(), "state.json"),
+			RestartWindow: models.Duration(time.Hour),
+		},
+	}
+	restarts := func(run string) int {
+		t.Helper()
+		_, result := runCheck(t, c)
+		r := result.Data.([]checker.ProcessResult)[0].Restarts
+		if r == nil {
+			t.Fatalf("%s: restarts not reported", run)
+		}
+		return r.Count
+	}
+
+	first := startSleep(t, arg)
+	restarts("first run")
+
+	// - A second instance joins, nothing is lost: a scale-up
+	startSleep(t, arg)
+	if got := restarts("scale-up"); got != 0 {
+		t.Errorf("scale-up: expected 0 restarts, got %d", got)
+	}
+
+	// - One instance dies and a new one takes its place: a restart
+	first.Process.Kill()
+	first.Wait()
+	startSleep(t, arg)
+	if got := restarts("replacement"); got != 1 {
+		t.Errorf("replacement: expected 1 restart, got %d", got)
+	}
+}
+
+func TestProcessCheckCorruptState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(stateFile, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := &checker.ProcessCheck{
+		Specs:   []models.ProcessSpec{{Pidfile: writePidfile(t, os.Getpid()), Min: 1}},
+		Options: checker.ProcessOptions{StateFile: stateFile, RestartWindow: models.Duration(time.Hour)},
+	}
+
+	_, result := runCheck(t, c)
+	if result.Status != models.StatusUnknown {
+		t.Errorf("corrupt state: expected UNKNOWN, got %s", result.Status)
+	}
+	// The state file is replaced, so the next run works again
+	_, result = runCheck(t, c)
+	if result.Status != models.StatusOK {
+		t.Errorf("after corrupt state: expected OK, got %s", result.Status)
+	}
+}
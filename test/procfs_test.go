@@ -0,0 +1,222 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andinianst93/system-health-checker/internal/checker"
+	"github.com/andinianst93/system-health-checker/internal/models"
+)
+
+// fixtureProcRoot is a procfs snapshot used by checks that read /proc directly
+const fixtureProcRoot = "testdata/proc"
+
+// runCheck collects and evaluates a single check against default thresholds
+func runCheck(t *testing.T, c checker.Check) (*models.SystemMetrics, *models.CheckResult) {
+	t.Helper()
+	if err := c.Collect(context.Background()); err != nil {
+		t.Fatalf("%s: collect failed: %v", c.Name(), err)
+	}
+	metrics := models.NewSystemMetrics()
+	return metrics, c.Evaluate(metrics, models.NewDefaultThresholds())
+}
+
+func TestPressureCheckFixtures(t *testing.T) {
+	metrics, result := runCheck(t, &checker.PressureCheck{ProcRoot: fixtureProcRoot})
+
+	want := map[string]models.Status{
+		"cpu":    models.StatusOK,
+		"memory": models.StatusWarning,
+		"io":     models.StatusCritical,
+	}
+	if len(metrics.Pressure) != len(want) {
+		t.Fatalf("expected %d resources, got %d", len(want), len(metrics.Pressure))
+	}
+	thresholds := models.NewDefaultThresholds()
+	for _, p := range metrics.Pressure {
+		if got := p.GetStatus(thresholds); got != want[p.Resource] {
+			t.Errorf("%s: expected %s, got %s", p.Resource, want[p.Resource], got)
+		}
+	}
+	if metrics.Pressure[1].Some.Avg10 != 12.4 || metrics.Pressure[1].Full == nil || metrics.Pressure[1].Full.Avg10 != 4.2 {
+		t.Errorf("memory pressure parsed incorrectly: %+v", metrics.Pressure[1])
+	}
+	if result.Status != models.StatusCritical {
+		t.Errorf("expected overall CRITICAL, got %s", result.Status)
+	}
+
+	// - "full" is judged too: memory full 4.2% under the default 5% is
+	//   fine, but a lower full critical level escalates memory to CRITICAL
+	thresholds.PSIMemoryFullWarning = 2
+	thresholds.PSIMemoryFullCritical = 4
+	if got := metrics.Pressure[1].GetStatus(thresholds); got != models.StatusCritical {
+		t.Errorf("memory: expected full pressure to escalate to CRITICAL, got %s", got)
+	}
+	// - io: full 30% is critical on its own even with "some" out of reach
+	thresholds.PSIIOWarning, thresholds.PSIIOCritical = 90, 95
+	if got := metrics.Pressure[2].GetStatus(thresholds); got != models.StatusCritical {
+		t.Errorf("io: expected full pressure CRITICAL, got %s", got)
+	}
+}
+
+func TestPressureCheckUnsupported(t *testing.T) {
+	_, result := runCheck(t, &checker.PressureCheck{ProcRoot: t.TempDir()})
+	if result.Status != models.StatusOK || len(result.Rows) != 1 {
+		t.Errorf("expected a single OK row without PSI, got %s with %d rows", result.Status, len(result.Rows))
+	}
+}
+
+func TestTCPCheckFixtures(t *testing.T) {
+	metrics, result := runCheck(t, &checker.TCPCheck{ProcRoot: fixtureProcRoot})
+
+	ti := metrics.TCP
+	want := map[string]int{"LISTEN": 3, "ESTABLISHED": 3, "TIME_WAIT": 3, "CLOSE_WAIT": 1}
+	for state, count := range want {
+		if ti.States[state] != count {
+			t.Errorf("%s: expected %d sockets, got %d", state, count, ti.States[state])
+		}
+	}
+
+	// - Ports 0x8000-0x8004 are in the 10 port range; 0x8003 is shared
+	if ti.EphemeralUsed != 5 || ti.GetEphemeralRange() != 10 {
+		t.Errorf("expected 5 of 10 ephemeral ports used, got %d of %d", ti.EphemeralUsed, ti.GetEphemeralRange())
+	}
+
+	// - Interval 0 averages the counters over the 1000s fixture uptime
+	if ti.ListenOverflowRate != 2 || ti.ListenDropRate != 3 {
+		t.Errorf("expected 2 overflows/s and 3 drops/s, got %v and %v", ti.ListenOverflowRate, ti.ListenDropRate)
+	}
+	if result.Status != models.StatusWarning {
+		t.Errorf("expected WARNING from listen overflows, got %s", result.Status)
+	}
+}
+
+// fixtureSysRoot is a sysfs snapshot used by checks that read /sys directly
+const fixtureSysRoot = "testdata/sys"
+
+func TestTemperatureCheckFixtures(t *testing.T) {
+	metrics, result := runCheck(t, &checker.TemperatureCheck{SysRoot: fixtureSysRoot})
+
+	cases := []struct {
+		chip, label       string
+		warning, critical float64
+		want              models.Status
+	}{
+		// - Kernel-provided *_max/*_crit limits
+		{chip: "coretemp", label: "Package id 0", warning: 84, critical: 100, want: models.StatusOK},
+		{chip: "coretemp", label: "Core 0", warning: 84, critical: 100, want: models.StatusWarning},
+		// - No limits: label falls back to the attribute name, limits to the defaults
+		{chip: "nvme", label: "temp1", warning: models.DefaultTempWarning, critical: models.DefaultTempCritical, want: models.StatusOK},
+		// - Thermal zone trip points
+		{chip: "thermal_zone0", label: "x86_pkg_temp", warning: 95, critical: 105, want: models.StatusWarning},
+	}
+	if len(metrics.Temperatures) != len(cases) {
+		t.Fatalf("expected %d sensors, got %d", len(cases), len(metrics.Temperatures))
+	}
+	thresholds := models.NewDefaultThresholds()
+	for i, c := range cases {
+		s := metrics.Temperatures[i]
+		if s.Chip != c.chip || s.Label != c.label {
+			t.Errorf("sensor %d: expected %s %s, got %s %s", i, c.chip, c.label, s.Chip, s.Label)
+			continue
+		}
+		warning, critical := s.Limits(thresholds)
+		if warning != c.warning || critical != c.critical {
+			t.Errorf("%s %s: expected limits %v/%v, got %v/%v", c.chip, c.label, c.warning, c.critical, warning, critical)
+		}
+		if got := s.GetStatus(thresholds); got != c.want {
+			t.Errorf("%s %s: expected %s, got %s", c.chip, c.label, c.want, got)
+		}
+	}
+	if result.Status != models.StatusWarning {
+		t.Errorf("expected overall WARNING, got %s", result.Status)
+	}
+
+	// - Configured thresholds override the sensor limits
+	thresholds.TempCritical = 90
+	if got := metrics.Temperatures[1].GetStatus(thresholds); got != models.StatusCritical {
+		t.Errorf("expected configured critical threshold to win, got %s", got)
+	}
+}
+
+func TestKernelTablesCheckFixtures(t *testing.T) {
+	metrics, result := runCheck(t, &checker.KernelTablesCheck{ProcRoot: fixtureProcRoot})
+
+	ki := metrics.KernelTables
+	thresholds := models.NewDefaultThresholds()
+	if ki.GetFileHandlePercent() != 85 || ki.GetFileHandleStatus(thresholds) != models.StatusWarning {
+		t.Errorf("file handles: expected 85%% WARNING, got %v%% %s", ki.GetFileHandlePercent(), ki.GetFileHandleStatus(thresholds))
+	}
+	// - threads-max (20000) is lower than pid_max (32768), so it is the limit
+	if ki.Tasks != 1200 || ki.GetPIDPercent() != 6 {
+		t.Errorf("tasks: expected 1200 at 6%%, got %d at %v%%", ki.Tasks, ki.GetPIDPercent())
+	}
+	if !ki.HasConntrack || ki.GetConntrackStatus(thresholds) != models.StatusWarning {
+		t.Errorf("conntrack: expected WARNING, got %v %s", ki.HasConntrack, ki.GetConntrackStatus(thresholds))
+	}
+	if result.Status != models.StatusWarning {
+		t.Errorf("expected overall WARNING, got %s", result.Status)
+	}
+}
+
+func TestLinkUpFixtures(t *testing.T) {
+	cases := []struct {
+		name    string
+		adminUp bool
+		want    bool
+	}{
+		// - Admin up but no carrier: operstate wins
+		{name: "eth0", adminUp: true, want: false},
+		{name: "eth1", adminUp: true, want: true},
+		// - operstate "unknown" (loopback) falls back to the admin flag
+		{name: "lo", adminUp: true, want: true},
+		{name: "lo", adminUp: false, want: false},
+		// - No operstate at all falls back to the admin flag
+		{name: "wlan0", adminUp: true, want: true},
+	}
+	for _, c := range cases {
+		if got := checker.LinkUp(fixtureSysRoot, c.name, c.adminUp); got != c.want {
+			t.Errorf("%s (admin up %v): expected %v, got %v", c.name, c.adminUp, c.want, got)
+		}
+	}
+}
+
+// writeMountinfo writes a one-line mount table for /data to <root>/1/mountinfo
+func writeMountinfo(t *testing.T, root, options string) {
+	t.Helper()
+	dir := filepath.Join(root, "1")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := "36 25 8:17 / /data " + options + " shared:1 - ext4 /dev/sdb1 " + options + ",errors=remount-ro\n"
+	if err := os.WriteFile(filepath.Join(dir, "mountinfo"), []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMountCheckFlagsReadOnlyRemountByDefault(t *testing.T) {
+	root := t.TempDir()
+	opts := checker.NewDefaultOptions()
+
+	writeMountinfo(t, root, "rw,relatime")
+	metrics, result := runCheck(t, &checker.MountCheck{ProcRoot: root, Options: opts.Disk})
+	if len(metrics.Mounts) != 1 || result.Status != models.StatusOK {
+		t.Fatalf("rw /data: expected one OK mount, got %d mounts, %s", len(metrics.Mounts), result.Status)
+	}
+
+	// - The kernel remounts /data read-only after an error
+	writeMountinfo(t, root, "ro,relatime")
+	_, result = runCheck(t, &checker.MountCheck{ProcRoot: root, Options: opts.Disk})
+	if result.Status != models.StatusCritical {
+		t.Errorf("ro /data: expected CRITICAL without configuration, got %s", result.Status)
+	}
+
+	// - An empty writable list disables the check
+	opts.Disk.WritableMounts = []string{}
+	_, result = runCheck(t, &checker.MountCheck{ProcRoot: root, Options: opts.Disk})
+	if result.Status != models.StatusOK {
+		t.Errorf("ro /data with no writable patterns: expected OK, got %s", result.Status)
+	}
+}
@@ -0,0 +1,214 @@
+package test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/andinianst93/system-health-checker/internal/checker"
+	"github.com/andinianst93/system-health-checker/internal/models"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+// approx reports whether two rates are equal up to float rounding
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNetRates(t *testing.T) {
+	before := net.IOCountersStat{
+		BytesRecv: 1000, BytesSent: 2000, PacketsRecv: 10, PacketsSent: 20,
+		Errin: 1, Errout: 1, Dropin: 5, Dropout: 0,
+	}
+	after := net.IOCountersStat{
+		BytesRecv: 5000, BytesSent: 4000, PacketsRecv: 50, PacketsSent: 40,
+		Errin: 3, Errout: 3, Dropin: 25, Dropout: 4,
+	}
+	info := checker.NetRates("eth0", before, after, 2*time.Second)
+
+	cases := []struct {
+		name      string
+		got, want float64
+	}{
+		{name: "rx bytes", got: info.RxBytesPerSec, want: 2000},
+		{name: "tx bytes", got: info.TxBytesPerSec, want: 1000},
+		{name: "rx packets", got: info.RxPacketsPerSec, want: 20},
+		{name: "tx packets", got: info.TxPacketsPerSec, want: 10},
+		// - Errors and drops combine rx and tx
+		{name: "errors", got: info.ErrorsPerSec, want: 2},
+		{name: "drops", got: info.DropsPerSec, want: 12},
+	}
+	for _, c := range cases {
+		if !approx(c.got, c.want) {
+			t.Errorf("%s: expected %v/s, got %v/s", c.name, c.want, c.got)
+		}
+	}
+
+	// - A counter reset yields zero instead of a huge rate
+	reset := checker.NetRates("eth0", after, before, 2*time.Second)
+	if reset.RxBytesPerSec != 0 || reset.ErrorsPerSec != 0 {
+		t.Errorf("counter reset: expected zero rates, got %+v", reset)
+	}
+}
+
+func TestNetInterfaceRatesSkipsNewInterfaces(t *testing.T) {
+	before := []net.IOCountersStat{{Name: "eth0", BytesRecv: 1000, Errin: 0}}
+	after := []net.IOCountersStat{
+		{Name: "eth0", BytesRecv: 3000, Errin: 0},
+		// veth created during the window, with counters since its creation
+		{Name: "veth1", BytesRecv: 1 << 30, Errin: 500},
+	}
+
+	rates := checker.NetInterfaceRates(before, after, time.Second, true)
+	if _, ok := rates["veth1"]; ok {
+		t.Errorf("expected veth1 to be left out of a sampled window, got %+v", rates["veth1"])
+	}
+	if eth0, ok := rates["eth0"]; !ok || !approx(eth0.RxBytesPerSec, 2000) {
+		t.Errorf("expected eth0 at 2000 B/s, got %+v", eth0)
+	}
+
+	// Without a first sample the counters are averages since boot
+	rates = checker.NetInterfaceRates(nil, after, 1000*time.Second, false)
+	if veth, ok := rates["veth1"]; !ok || !approx(veth.ErrorsPerSec, 0.5) {
+		t.Errorf("expected veth1 since-boot error rate 0.5/s, got %+v", veth)
+	}
+}
+
+func TestCPUDelta(t *testing.T) {
+	before := cpu.TimesStat{User: 100, System: 50, Idle: 1000, Nice: 5, Iowait: 20, Irq: 1, Softirq: 2, Steal: 3}
+	after := cpu.TimesStat{User: 160, System: 70, Idle: 1100, Nice: 10, Iowait: 30, Irq: 2, Softirq: 4, Steal: 8}
+	got := checker.CPUDelta(before, after)
+	want := cpu.TimesStat{User: 60, System: 20, Idle: 100, Nice: 5, Iowait: 10, Irq: 1, Softirq: 2, Steal: 5}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestCPUBusyAndBreakdown(t *testing.T) {
+	cases := []struct {
+		name   string
+		before cpu.TimesStat
+		after  cpu.TimesStat
+		busy   float64
+		times  models.CPUTimes
+	}{
+		{
+			name:   "idle",
+			before: cpu.TimesStat{Idle: 100},
+			after:  cpu.TimesStat{Idle: 200},
+			busy:   0,
+			times:  models.CPUTimes{Idle: 100},
+		},
+		{
+			// nice counts as user, irq and softirq as system
+			name:   "mixed",
+			before: cpu.TimesStat{User: 10, System: 10, Idle: 10},
+			after:  cpu.TimesStat{User: 40, Nice: 10, System: 20, Irq: 5, Softirq: 5, Idle: 50},
+			busy:   60,
+			times:  models.CPUTimes{User: 40, System: 20, Idle: 40},
+		},
+		{
+			// time waiting on I/O is not busy
+			name:   "iowait",
+			before: cpu.TimesStat{User: 0, Idle: 0, Iowait: 0},
+			after:  cpu.TimesStat{User: 25, Idle: 25, Iowait: 50},
+			busy:   25,
+			times:  models.CPUTimes{User: 25, Iowait: 50, Idle: 25},
+		},
+		{
+			// stolen time is busy from the guest's point of view
+			name:   "steal",
+			before: cpu.TimesStat{User: 100, Idle: 100, Steal: 100},
+			after:  cpu.TimesStat{User: 150, Idle: 120, Steal: 130},
+			busy:   80,
+			times:  models.CPUTimes{User: 50, Steal: 30, Idle: 20},
+		},
+		{
+			// no time elapsed: nothing to divide by
+			name:   "empty",
+			before: cpu.TimesStat{User: 10, Idle: 10},
+			after:  cpu.TimesStat{User: 10, Idle: 10},
+			busy:   0,
+			times:  models.CPUTimes{},
+		},
+	}
+	for _, c := range cases {
+		delta := checker.CPUDelta(c.before, c.after)
+		if got := checker.CPUBusyPercent(delta); !approx(got, c.busy) {
+			t.Errorf("%s: expected busy %v%%, got %v%%", c.name, c.busy, got)
+		}
+		got := checker.CPUBreakdown(delta)
+		for _, f := range []struct {
+			field     string
+			got, want float64
+		}{
+			{"user", got.User, c.times.User},
+			{"system", got.System, c.times.System},
+			{"iowait", got.Iowait, c.times.Iowait},
+			{"steal", got.Steal, c.times.Steal},
+			{"idle", got.Idle, c.times.Idle},
+		} {
+			if !approx(f.got, f.want) {
+				t.Errorf("%s: expected %s %v%%, got %v%%", c.name, f.field, f.want, f.got)
+			}
+		}
+	}
+}
+
+func TestDiskIORates(t *testing.T) {
+	before := disk.IOCountersStat{
+		ReadCount: 100, WriteCount: 200, ReadBytes: 1 << 20, WriteBytes: 2 << 20,
+		ReadTime: 1000, WriteTime: 3000, IoTime: 5000,
+	}
+	after := disk.IOCountersStat{
+		ReadCount: 140, WriteCount: 260, ReadBytes: 5 << 20, WriteBytes: 4 << 20,
+		ReadTime: 1200, WriteTime: 3800, IoTime: 6500,
+	}
+	info := checker.DiskIORates("sda", before, after, 2*time.Second)
+
+	cases := []struct {
+		name      string
+		got, want float64
+	}{
+		{"read bytes/s", info.ReadBytesPerSec, 2 << 20},
+		{"write bytes/s", info.WriteBytesPerSec, 1 << 20},
+		{"read IOPS", info.ReadIOPS, 20},
+		{"write IOPS", info.WriteIOPS, 30},
+		// 1000ms waited over 100 completed requests
+		{"await", info.AwaitMs, 10},
+		// 1500ms busy in a 2000ms window
+		{"util", info.UtilPercent, 75},
+	}
+	for _, c := range cases {
+		if !approx(c.got, c.want) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, c.got)
+		}
+	}
+
+	// No completed requests: await is 0, and util is capped at 100%
+	idle := after
+	idle.IoTime += 5000
+	info = checker.DiskIORates("sda", after, idle, 2*time.Second)
+	if info.AwaitMs != 0 || info.UtilPercent != 100 {
+		t.Errorf("expected await 0 and util 100%%, got %v / %v", info.AwaitMs, info.UtilPercent)
+	}
+
+	// A zero or sub-millisecond window yields no rates, never NaN or Inf
+	for _, window := range []time.Duration{0, 500 * time.Microsecond} {
+		info = checker.DiskIORates("sda", before, after, window)
+		if info.ReadIOPS != 0 || info.UtilPercent != 0 {
+			t.Errorf("%s window: expected zero rates, got %+v", window, info)
+		}
+	}
+
+	// Utilization uses the exact window, not one truncated to milliseconds:
+	// 3ms busy in 3.75ms is 80%, not 100%
+	busy := before
+	busy.IoTime += 3
+	info = checker.DiskIORates("sda", before, busy, 3750*time.Microsecond)
+	if !approx(info.UtilPercent, 80) {
+		t.Errorf("expected util 80%%, got %v", info.UtilPercent)
+	}
+}